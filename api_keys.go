@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -22,9 +23,9 @@ type ApiKey struct {
 type ApiKeyLinks struct{}
 
 type apiKeyResponse struct {
-	ApiKeys []ApiKey               `json:"api_keys"`
-	Links   *apiKeyResponseLinks   `json:"links"`
-	Meta    map[string]interface{} `json:"meta"`
+	ApiKeys []ApiKey             `json:"api_keys"`
+	Links   *apiKeyResponseLinks `json:"links"`
+	Meta    *PaginationParams    `json:"meta"`
 }
 
 type apiKeyResponseLinks struct{}
@@ -34,9 +35,15 @@ type ApiKeyPage struct {
 	*PaginationParams
 }
 
+type ApiKeyIterator = Iterator[ApiKey]
+
 func (s *ApiKeyService) Create() (*ApiKey, *http.Response, error) {
+	return s.CreateContext(context.Background())
+}
+
+func (s *ApiKeyService) CreateContext(ctx context.Context) (*ApiKey, *http.Response, error) {
 	apiKeyResponse := new(apiKeyResponse)
-	httpResponse, err := s.client.POST("/api_keys", nil, nil, apiKeyResponse)
+	httpResponse, err := s.client.POSTContext(ctx, "/api_keys", nil, nil, apiKeyResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -44,9 +51,13 @@ func (s *ApiKeyService) Create() (*ApiKey, *http.Response, error) {
 }
 
 func (s *ApiKeyService) Fetch(id string) (*ApiKey, *http.Response, error) {
+	return s.FetchContext(context.Background(), id)
+}
+
+func (s *ApiKeyService) FetchContext(ctx context.Context, id string) (*ApiKey, *http.Response, error) {
 	path := fmt.Sprintf("/api_keys/%v", id)
 	apiKeyResponse := new(apiKeyResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, apiKeyResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, apiKeyResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -54,21 +65,85 @@ func (s *ApiKeyService) Fetch(id string) (*ApiKey, *http.Response, error) {
 }
 
 func (s *ApiKeyService) List(args ...interface{}) (*ApiKeyPage, *http.Response, error) {
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *ApiKeyService) ListContext(ctx context.Context, args ...interface{}) (*ApiKeyPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/api_keys", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *ApiKeyService) ListWith(opts *ListOptions) (*ApiKeyPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *ApiKeyService) ListWithContext(ctx context.Context, opts *ListOptions) (*ApiKeyPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *ApiKeyService) list(ctx context.Context, path string, query map[string]interface{}) (*ApiKeyPage, *http.Response, error) {
 	apiKeyResponse := new(apiKeyResponse)
-	httpResponse, err := s.client.GET("/api_keys", query, nil, apiKeyResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, apiKeyResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &ApiKeyPage{
 		ApiKeys:          apiKeyResponse.ApiKeys,
-		PaginationParams: NewPaginationParams(apiKeyResponse.Meta),
+		PaginationParams: apiKeyResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of API keys at href, such as the Next or Previous
+// link of another page.
+func (s *ApiKeyService) FetchPage(href string) (*ApiKeyPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *ApiKeyService) FetchPageContext(ctx context.Context, href string) (*ApiKeyPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all API keys, fetching pages as needed. args are
+// the same as for List.
+func (s *ApiKeyService) Iter(args ...interface{}) *ApiKeyIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *ApiKeyService) IterContext(ctx context.Context, args ...interface{}) *ApiKeyIterator {
+	return newIterator(ctx, "/api_keys", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *ApiKeyService) IterWith(opts *ListOptions) *ApiKeyIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *ApiKeyService) IterWithContext(ctx context.Context, opts *ListOptions) *ApiKeyIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *ApiKeyService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]ApiKey, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.ApiKeys, page.PaginationParams, nil
+}
+
 func (s *ApiKeyService) Delete(id string) (bool, *http.Response, error) {
+	return s.DeleteContext(context.Background(), id)
+}
+
+func (s *ApiKeyService) DeleteContext(ctx context.Context, id string) (bool, *http.Response, error) {
 	path := fmt.Sprintf("/api_keys/%v", id)
-	httpResponse, err := s.client.DELETE(path, nil, nil, nil)
+	httpResponse, err := s.client.DELETEContext(ctx, path, nil, nil, nil)
 	if err != nil {
 		return false, httpResponse, err
 	}
@@ -2,12 +2,16 @@ package balanced
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 const (
@@ -15,6 +19,12 @@ const (
 	ApiVersion = "v1.1"
 	baseUrlStr = "https://api.balancedpayments.com/"
 
+	// DefaultApiRevision is the API revision requested unless the client is
+	// created with WithApiRevision.
+	DefaultApiRevision = "1.1"
+
+	defaultUserAgent = "balanced-go/1.1"
+
 	Pending   = "pending"
 	Succeeded = "succeeded"
 	Failed    = "failed"
@@ -39,28 +49,64 @@ type Client struct {
 	client *http.Client
 	secret string
 
-	ApiKey       *ApiKeyService
-	BankAccount  *BankAccountService
-	Verification *VerificationService
-	Callback     *CallbackService
-	Card         *CardService
-	CardHold     *CardHoldService
-	Credit       *CreditService
-	Customer     *CustomerService
-	Debit        *DebitService
-	Event        *EventService
-	Order        *OrderService
-	Refund       *RefundService
-	Reversal     *ReversalService
-	Marketplace  *MarketplaceService
-}
-
-func NewClient(httpClient *http.Client, secret string) *Client {
+	baseURL     *url.URL
+	apiRevision string
+	userAgent   string
+	header      http.Header
+	timeout     time.Duration
+	logger      Logger
+
+	// err records an invalid option; it is returned by every request.
+	err error
+
+	// RetryPolicy controls whether and how Do retries failed requests. When
+	// nil, every request is attempted exactly once.
+	RetryPolicy *RetryPolicy
+
+	// IdempotencyCache remembers the responses to requests whose idempotency
+	// key was chosen with WithIdempotencyKey, so that repeating such a request
+	// replays the original response. When nil, as it is by default, nothing is
+	// remembered.
+	IdempotencyCache *IdempotencyCache
+
+	ApiKey            *ApiKeyService
+	BankAccount       *BankAccountService
+	Verification      *VerificationService
+	Callback          *CallbackService
+	Card              *CardService
+	CardHold          *CardHoldService
+	Credit            *CreditService
+	Customer          *CustomerService
+	Debit             *DebitService
+	Dispute           *DisputeService
+	Event             *EventService
+	Order             *OrderService
+	Refund            *RefundService
+	Reversal          *ReversalService
+	Marketplace       *MarketplaceService
+	Transaction       *TransactionService
+	FundingInstrument *FundingInstrumentService
+}
+
+// NewClient returns a client that authenticates with secret. A nil httpClient
+// means http.DefaultClient. The client talks to the Balanced API using
+// revision DefaultApiRevision unless opts say otherwise.
+func NewClient(httpClient *http.Client, secret string, opts ...ClientOption) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
-	c := &Client{client: httpClient, secret: secret}
+	c := &Client{
+		client:      httpClient,
+		secret:      secret,
+		baseURL:     baseURL,
+		apiRevision: DefaultApiRevision,
+		userAgent:   defaultUserAgent,
+		header:      make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
 	c.ApiKey = &ApiKeyService{client: c}
 	c.BankAccount = &BankAccountService{client: c}
 	c.Verification = &VerificationService{client: c}
@@ -70,33 +116,54 @@ func NewClient(httpClient *http.Client, secret string) *Client {
 	c.Credit = &CreditService{client: c}
 	c.Customer = &CustomerService{client: c}
 	c.Debit = &DebitService{client: c}
+	c.Dispute = &DisputeService{client: c}
 	c.Event = &EventService{client: c}
 	c.Order = &OrderService{client: c}
 	c.Refund = &RefundService{client: c}
 	c.Reversal = &ReversalService{client: c}
 	c.Marketplace = &MarketplaceService{client: c}
+	c.Transaction = &TransactionService{client: c}
+	c.FundingInstrument = &FundingInstrumentService{client: c}
 
 	return c
 }
 
 func (c *Client) GET(urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
-	return c.buildAndDoRequest("GET", urlPath, query, reqBody, resObj)
+	return c.GETContext(context.Background(), urlPath, query, reqBody, resObj)
 }
 
 func (c *Client) POST(urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
-	return c.buildAndDoRequest("POST", urlPath, query, reqBody, resObj)
+	return c.POSTContext(context.Background(), urlPath, query, reqBody, resObj)
 }
 
 func (c *Client) PUT(urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
-	return c.buildAndDoRequest("PUT", urlPath, query, reqBody, resObj)
+	return c.PUTContext(context.Background(), urlPath, query, reqBody, resObj)
 }
 
 func (c *Client) DELETE(urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
-	return c.buildAndDoRequest("DELETE", urlPath, query, reqBody, resObj)
+	return c.DELETEContext(context.Background(), urlPath, query, reqBody, resObj)
+}
+
+// GETContext is like GET, but the request is bound to ctx, so it is aborted
+// as soon as ctx is canceled or its deadline passes.
+func (c *Client) GETContext(ctx context.Context, urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
+	return c.buildAndDoRequest(ctx, "GET", urlPath, query, reqBody, resObj)
 }
 
-func (c *Client) buildAndDoRequest(method, urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
-	req, err := c.NewRequest(method, urlPath, query, reqBody)
+func (c *Client) POSTContext(ctx context.Context, urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
+	return c.buildAndDoRequest(ctx, "POST", urlPath, query, reqBody, resObj)
+}
+
+func (c *Client) PUTContext(ctx context.Context, urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
+	return c.buildAndDoRequest(ctx, "PUT", urlPath, query, reqBody, resObj)
+}
+
+func (c *Client) DELETEContext(ctx context.Context, urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
+	return c.buildAndDoRequest(ctx, "DELETE", urlPath, query, reqBody, resObj)
+}
+
+func (c *Client) buildAndDoRequest(ctx context.Context, method, urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
+	req, err := c.NewRequestContext(ctx, method, urlPath, query, reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -111,13 +178,45 @@ func mapToQueryVals(params map[string]interface{}) url.Values {
 	return values
 }
 
-func (c *Client) NewRequest(method, urlPath string, queryParams map[string]interface{}, body interface{}) (*http.Request, error) {
-	url, err := url.Parse(urlPath)
+// ErrForeignUrl is returned for a request to an absolute URL on another host
+// than the client's base URL, to which the client would send its secret.
+var ErrForeignUrl = errors.New("balanced: URL is not on the API host")
+
+// resolveUrl turns urlPath, which is usually an href such as "/cards/CC123",
+// into an absolute URL under the client's base URL. An absolute urlPath must
+// have the scheme and host of the base URL.
+func (c *Client) resolveUrl(urlPath string) (*url.URL, error) {
+	ref, err := url.Parse(urlPath)
 	if err != nil {
 		return nil, err
 	}
+	if ref.IsAbs() {
+		if !strings.EqualFold(ref.Scheme, c.baseURL.Scheme) || !strings.EqualFold(ref.Host, c.baseURL.Host) {
+			return nil, fmt.Errorf("%w: %v", ErrForeignUrl, urlPath)
+		}
+		return ref, nil
+	}
+	u := *c.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.TrimPrefix(ref.Path, "/")
+	u.RawPath = ""
+	u.RawQuery = ref.RawQuery
+	return &u, nil
+}
+
+func (c *Client) NewRequest(method, urlPath string, queryParams map[string]interface{}, body interface{}) (*http.Request, error) {
+	return c.NewRequestContext(context.Background(), method, urlPath, queryParams, body)
+}
+
+// NewRequestContext is like NewRequest, but the returned request carries ctx.
+func (c *Client) NewRequestContext(ctx context.Context, method, urlPath string, queryParams map[string]interface{}, body interface{}) (*http.Request, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
 
-	u := baseURL.ResolveReference(url)
+	u, err := c.resolveUrl(urlPath)
+	if err != nil {
+		return nil, err
+	}
 
 	if queryParams != nil {
 		qs := mapToQueryVals(queryParams)
@@ -135,18 +234,22 @@ func (c *Client) NewRequest(method, urlPath string, queryParams map[string]inter
 		}
 	}
 
-	req, err := http.NewRequest(method, u.String(), buff)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), buff)
 	if err != nil {
 		return nil, err
 	}
 
+	for key, values := range c.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
 	// req.Header.Set("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/vnd.api+json;revision=1.1")
+	req.Header.Set("Accept", fmt.Sprintf("application/vnd.api+json;revision=%v", c.apiRevision))
 
 	if body != nil {
-		req.Header.Add("Content-Type", "application/json")
+		req.Header.Set("Content-Type", "application/json")
 	}
-	req.Header.Add("User-Agent", "balanced-go/1.1")
+	req.Header.Set("User-Agent", c.userAgent)
 
 	if c.secret != "" {
 		req.SetBasicAuth(c.secret, "")
@@ -155,77 +258,183 @@ func (c *Client) NewRequest(method, urlPath string, queryParams map[string]inter
 	return req, nil
 }
 
-// Do sends an API request and returns an API response
+// Do sends an API request and returns an API response. The request is
+// canceled when the context of req is done, including while the response
+// body is being read. Failed attempts are retried according to
+// c.RetryPolicy.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
+	for attempt := 1; ; attempt++ {
+		res, err := c.do(req, v)
+		if !c.RetryPolicy.shouldRetry(req, res, err, attempt) {
+			return res, err
+		}
+		ctx := req.Context()
+		if err := sleepContext(ctx, c.RetryPolicy.backoff(attempt, res)); err != nil {
+			return res, err
+		}
+		req = req.Clone(ctx)
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return res, err
+			}
+		}
+	}
+}
+
+// do makes a single attempt at sending req, unless the response to it can be
+// replayed from c.IdempotencyCache.
+func (c *Client) do(req *http.Request, v interface{}) (*http.Response, error) {
+	// Only a key chosen by the caller can be sent again, so the responses to
+	// requests with a generated key are not worth remembering.
+	key, ok := IdempotencyKeyFromContext(req.Context())
+	idempotent := c.IdempotencyCache != nil && ok && key == req.Header.Get(IdempotencyKeyHeader)
+
+	var res *http.Response
+	var replayed bool
+	if idempotent {
+		res, replayed = c.IdempotencyCache.replay(req)
+	}
+	if !replayed {
+		if c.timeout > 0 {
+			ctx, cancel := context.WithTimeout(req.Context(), c.timeout)
+			defer cancel()
+			req = req.WithContext(ctx)
+		}
+		start := time.Now()
+		var err error
+		res, err = c.client.Do(req)
+		if c.logger != nil {
+			if err != nil {
+				c.logger.Printf("balanced: %v %v: %v (%v)", req.Method, req.URL, err, time.Since(start))
+			} else {
+				c.logger.Printf("balanced: %v %v: %v (%v)", req.Method, req.URL, res.StatusCode, time.Since(start))
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	defer res.Body.Close()
 
-	err = checkResponse(res)
+	err := checkResponse(res)
+	if err != nil {
+		return res, err
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return res, err
 	}
+	if idempotent && !replayed {
+		c.IdempotencyCache.remember(req, res, data)
+	}
 
 	if v != nil {
 		// If v implements the io.Writer interface, the raw response body will be
 		// written to v, without attempting to decode it first.
 		if w, ok := v.(io.Writer); ok {
-			io.Copy(w, res.Body)
-		} else {
-			err = json.NewDecoder(res.Body).Decode(v)
+			_, err = w.Write(data)
+		} else if len(data) > 0 {
+			err = json.Unmarshal(data, v)
+		}
+		if err != nil {
+			return res, err
 		}
 	}
 
 	return res, nil
 }
 
+// ErrorResponse is the error returned for any non-2xx response. Use
+// errors.Is with the Err* values to tell the kinds of failures apart, and
+// errors.As with *ErrorResponseError or *ValidationError for the details of
+// an individual error.
 type ErrorResponse struct {
 	// http response that caused this error
 	*http.Response
 
+	// All the errors reported by Balanced, in the order given
 	Errors []ErrorResponseError `json:"errors"`
+
+	// RequestId identifies the failed request to Balanced support
+	RequestId string `json:"-"`
+
+	// RawBody holds the start of the response body, which is useful when the
+	// response did not come from Balanced (e.g., an HTML page from a proxy).
+	RawBody string `json:"-"`
 }
 
 type ErrorResponseError struct {
-	Status       string `json:"status"`
-	CategoryCode string `json:"category_code"`
-	CategoryType string `json:"category_type"`
-	Description  string `json:"description"`
-	RequestId    string `json:"request_id"`
-	StatusCode   int    `json:"status_code"`
+	Status       string                 `json:"status"`
+	CategoryCode string                 `json:"category_code"` // e.g., "card-declined"
+	CategoryType string                 `json:"category_type"` // e.g., "request", "logical", "banking"
+	Description  string                 `json:"description"`
+	Extras       map[string]interface{} `json:"extras,omitempty"` // e.g., the invalid fields of a request
+	RequestId    string                 `json:"request_id"`
+	StatusCode   int                    `json:"status_code"`
 }
 
 func (r *ErrorResponse) Error() string {
-	errors := r.Errors
-	var errDescr string
-	if len(errors) > 0 {
-		errDescr = errors[0].Description
-	} else {
-		errDescr = ""
+	descrs := make([]string, 0, len(r.Errors))
+	for _, e := range r.Errors {
+		descrs = append(descrs, e.Description)
+	}
+	errDescr := strings.Join(descrs, "; ")
+	if errDescr == "" {
+		errDescr = http.StatusText(r.Response.StatusCode)
+		if body := strings.Join(strings.Fields(r.RawBody), " "); body != "" {
+			if len(body) > 200 {
+				body = body[:200] + "..."
+			}
+			errDescr += ": " + body
+		}
+	}
+	if r.RequestId != "" {
+		errDescr += fmt.Sprintf(" (request %v)", r.RequestId)
 	}
 	return fmt.Sprintf("%v %v: %d %v",
 		r.Response.Request.Method, r.Response.Request.URL,
 		r.Response.StatusCode, errDescr)
 }
 
+// maxErrorBodyLen bounds how much of an error response body is kept in
+// ErrorResponse.RawBody.
+const maxErrorBodyLen = 4096
+
+// maxErrorBodyRead bounds how much of an error response body is read, which
+// is plenty for the errors of the API; the rest, such as the tail of a large
+// HTML page from a proxy, is left unread.
+const maxErrorBodyRead = 64 << 10
+
 func checkResponse(res *http.Response) error {
 	if c := res.StatusCode; 200 <= c && c <= 299 {
 		return nil
 	}
 	errorResponse := &ErrorResponse{Response: res}
-	data, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return err
+	// A failure to read the body still leaves us with the status code and
+	// headers, so it is not reported on its own.
+	data, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBodyRead))
+	res.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	errorResponse.RawBody = string(data)
+	if len(errorResponse.RawBody) > maxErrorBodyLen {
+		errorResponse.RawBody = errorResponse.RawBody[:maxErrorBodyLen]
 	}
-	if data != nil {
-		err = json.Unmarshal(data, errorResponse)
-		// var dat map[string]interface{}
-		// err = json.Unmarshal(data, &dat)
-		if err != nil {
-			return err
+
+	// Proxies and load balancers may answer with HTML or nothing at all, in
+	// which case there are no Balanced errors to report.
+	var payload struct {
+		Errors []ErrorResponseError `json:"errors"`
+	}
+	if json.Unmarshal(data, &payload) == nil {
+		errorResponse.Errors = payload.Errors
+	}
+	for _, e := range errorResponse.Errors {
+		if e.RequestId != "" {
+			errorResponse.RequestId = e.RequestId
+			break
 		}
 	}
 	return errorResponse
@@ -1,9 +1,23 @@
 package balanced
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	. "gopkg.in/check.v1"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var sharedClient *Client
@@ -151,6 +165,47 @@ func TestNewMarketplace(t *testing.T) {
 	}
 }
 
+type MarketplaceSuite struct{}
+
+var _ = Suite(&MarketplaceSuite{})
+
+func mustFetchMarketplace(c *C) *Marketplace {
+	marketplacePage, _, err := sharedClient.Marketplace.List()
+	c.Assert(err, IsNil)
+	c.Assert(len(marketplacePage.Marketplaces), Equals, 1)
+	return &marketplacePage.Marketplaces[0]
+}
+
+func (s *MarketplaceSuite) TestFetch(c *C) {
+	marketplace := mustFetchMarketplace(c)
+	fetchedMarketplace, _, err := sharedClient.Marketplace.Fetch(marketplace.Id)
+	c.Assert(err, IsNil)
+	c.Assert(fetchedMarketplace.Id, Equals, marketplace.Id)
+	c.Assert(fetchedMarketplace.Production, Equals, false)
+}
+
+func (s *MarketplaceSuite) TestUpdate(c *C) {
+	marketplace := mustFetchMarketplace(c)
+	updatedMarketplace, _, err := sharedClient.Marketplace.Update(marketplace.Id, map[string]interface{}{
+		"meta": map[string]interface{}{"updated": "yes"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(updatedMarketplace.Meta["updated"], Equals, "yes")
+}
+
+func (s *MarketplaceSuite) TestFetchOwner(c *C) {
+	marketplace := mustFetchMarketplace(c)
+	owner, err := sharedClient.Marketplace.FetchOwner(marketplace)
+	c.Assert(err, IsNil)
+	c.Assert(owner.Customer.Id, Equals, marketplace.Links.Owner)
+	c.Assert(len(owner.BankAccounts) > 0, Equals, true)
+}
+
+func (s *MarketplaceSuite) TestFetchOwnerWithoutLink(c *C) {
+	_, err := sharedClient.Marketplace.FetchOwner(&Marketplace{Id: "MP123"})
+	c.Assert(err, NotNil)
+}
+
 type ApiKeySuite struct{}
 
 var _ = Suite(&ApiKeySuite{})
@@ -485,6 +540,97 @@ func (s *CustomerSuite) TestAssociateWithBankAccount(c *C) {
 	c.Assert(updatedAccount.Links.Customer, Equals, customer.Id)
 }
 
+func (s *CustomerSuite) TestListCards(c *C) {
+	card := mustCreateCard(sharedClient)
+	defer deleteCard(sharedClient, card, c)
+
+	customer := mustCreateCustomer(sharedClient)
+	defer deleteCustomer(sharedClient, customer.Id, c)
+
+	_, _, err := sharedClient.Customer.AssociateWithCard(customer.Id, card.Id)
+	c.Assert(err, IsNil)
+
+	cardPage, _, err := sharedClient.Customer.ListCards(customer.Id)
+	c.Assert(err, IsNil)
+	c.Assert(cardPage.Total, Equals, 1)
+	c.Assert(cardPage.Cards[0].Id, Equals, card.Id)
+}
+
+func (s *CustomerSuite) TestListDebits(c *C) {
+	card := mustCreateCardFixture(sharedClient, "VisaSuccess")
+	defer deleteCard(sharedClient, card, c)
+
+	customer := mustCreateCustomer(sharedClient)
+	defer deleteCustomer(sharedClient, customer.Id, c)
+
+	_, _, err := sharedClient.Customer.AssociateWithCard(customer.Id, card.Id)
+	c.Assert(err, IsNil)
+	debit, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	debitPage, _, err := sharedClient.Customer.ListDebits(customer.Id)
+	c.Assert(err, IsNil)
+	c.Assert(debitPage.Total, Equals, 1)
+	c.Assert(debitPage.Debits[0].Id, Equals, debit.Id)
+
+	var ids []string
+	for debit, err := range sharedClient.Customer.IterDebits(customer.Id).All() {
+		c.Assert(err, IsNil)
+		ids = append(ids, debit.Id)
+	}
+	c.Assert(ids, DeepEquals, []string{debit.Id})
+}
+
+func (s *CustomerSuite) TestListWithoutActivity(c *C) {
+	customer := mustCreateCustomer(sharedClient)
+	defer deleteCustomer(sharedClient, customer.Id, c)
+
+	creditPage, _, err := sharedClient.Customer.ListCredits(customer.Id)
+	c.Assert(err, IsNil)
+	c.Assert(creditPage.Total, Equals, 0)
+
+	orderPage, _, err := sharedClient.Customer.ListOrders(customer.Id)
+	c.Assert(err, IsNil)
+	c.Assert(orderPage.Total, Equals, 0)
+
+	_, _, err = sharedClient.Customer.ListRefunds(customer.Id, "bad")
+	c.Assert(err, NotNil)
+}
+
+func (s *CustomerSuite) TestUpdateWith(c *C) {
+	customer := mustCreateCustomer(sharedClient)
+	defer deleteCustomer(sharedClient, customer.Id, c)
+
+	updatedCustomer, _, err := sharedClient.Customer.UpdateWith(customer.Id, &CustomerUpdate{
+		Name:     String("Jane Doe"),
+		DobMonth: Int(7),
+	})
+	c.Assert(err, IsNil)
+	c.Assert(updatedCustomer.Name, Equals, "Jane Doe")
+	c.Assert(updatedCustomer.DobMonth, Equals, 7)
+}
+
+func (s *CustomerSuite) TestUpdateWithInvalidFields(c *C) {
+	_, _, err := sharedClient.Customer.UpdateWith("CU123", &CustomerUpdate{
+		Email:    String("not an email"),
+		SsnLast4: String("12"),
+		Meta:     map[string]interface{}{"": "blank"},
+	})
+	c.Assert(errors.Is(err, ErrValidation), Equals, true)
+
+	var validationErr *ValidationError
+	c.Assert(errors.As(err, &validationErr), Equals, true)
+	c.Assert(validationErr.Fields["email"], Equals, "must be an email address")
+	c.Assert(validationErr.Fields["ssn_last4"], Equals, "must be 4 digits")
+	c.Assert(validationErr.Fields["meta"], Equals, "keys must not be blank")
+	c.Assert(validationErr.CategoryCode, Equals, "")
+
+	_, _, err = sharedClient.Customer.UpdateWith("CU123", nil)
+	c.Assert(errors.Is(err, ErrValidation), Equals, true)
+	_, _, err = sharedClient.Debit.UpdateWith("WD123", nil)
+	c.Assert(errors.Is(err, ErrValidation), Equals, true)
+}
+
 type BankAccountSuite struct{}
 
 var _ = Suite(&BankAccountSuite{})
@@ -674,6 +820,7 @@ func (s *BankAccountSuite) TestDebitUnverifiedAccount(c *C) {
 	c.Assert(debit, IsNil)
 	c.Assert(res.StatusCode, Equals, 409)
 	c.Assert(err.(*ErrorResponse).Errors[0].CategoryCode, Equals, "funding-source-not-debitable")
+	c.Assert(errors.Is(err, ErrFundingSourceNotDebitable), Equals, true)
 }
 
 type VerificationSuite struct{}
@@ -1210,6 +1357,30 @@ func (s *DebitSuite) TestUpdate(c *C) {
 	c.Assert(updatedDebit.Meta["xxx"], Equals, "yyy")
 }
 
+func (s *DebitSuite) TestUpdateWith(c *C) {
+	card := mustCreateCard(sharedClient)
+	defer deleteCard(sharedClient, card, c)
+
+	debit, _, err := sharedClient.Card.Charge(card.Id, &Debit{
+		Amount:      50,
+		Description: "Test Charge",
+	})
+	c.Assert(err, IsNil)
+
+	updatedDebit, _, err := sharedClient.Debit.UpdateWith(debit.Id, &DebitUpdate{
+		Meta: map[string]string{"xxx": "yyy"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(updatedDebit.Description, Equals, "Test Charge")
+	c.Assert(updatedDebit.Meta["xxx"], Equals, "yyy")
+
+	updatedDebit, _, err = sharedClient.Debit.UpdateWith(debit.Id, &DebitUpdate{
+		Description: String("updated description"),
+	})
+	c.Assert(err, IsNil)
+	c.Assert(updatedDebit.Description, Equals, "updated description")
+}
+
 func (s *DebitSuite) TestRefund(c *C) {
 	card := mustCreateCard(sharedClient)
 	defer deleteCard(sharedClient, card, c)
@@ -1302,12 +1473,99 @@ type DisputeSuite struct{}
 
 var _ = Suite(&DisputeSuite{})
 
+// mustFetchAnyDispute returns a dispute of the test marketplace. Disputes on
+// charges to the "DiscoverDisputedCharge" card are only created some time
+// after the charge, so the test is skipped when there is none yet.
+func mustFetchAnyDispute(c *C) *Dispute {
+	card := mustCreateCardFixture(sharedClient, "DiscoverDisputedCharge")
+	_, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	disputePage, _, err := sharedClient.Dispute.List()
+	c.Assert(err, IsNil)
+	if len(disputePage.Disputes) == 0 {
+		c.Skip("No dispute has been created yet")
+	}
+	return &disputePage.Disputes[0]
+}
+
 func (s *DisputeSuite) TestFetch(c *C) {
-	c.Skip("Unimplemented")
+	dispute := mustFetchAnyDispute(c)
+	fetchedDispute, _, err := sharedClient.Dispute.Fetch(dispute.Id)
+	c.Assert(err, IsNil)
+	c.Assert(fetchedDispute.Id, Equals, dispute.Id)
+	c.Assert(fetchedDispute.Amount, Equals, dispute.Amount)
 }
 
 func (s *DisputeSuite) TestList(c *C) {
-	c.Skip("Unimplemented")
+	disputePage, _, err := sharedClient.Dispute.List()
+	c.Assert(err, IsNil)
+	c.Assert(len(disputePage.Disputes) <= disputePage.Total, Equals, true)
+}
+
+func (s *DisputeSuite) TestListByStatus(c *C) {
+	disputePage, _, err := sharedClient.Dispute.ListByStatus(Pending)
+	c.Assert(err, IsNil)
+	for _, dispute := range disputePage.Disputes {
+		c.Assert(dispute.Status, Equals, Pending)
+	}
+}
+
+func (s *DisputeSuite) TestListByStatusArguments(c *C) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte(`{"disputes": [], "meta": {"total": 0}}`))
+	}))
+	defer server.Close()
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(server.URL))
+
+	_, _, err := client.Dispute.ListByStatusWith(Won, &ListOptions{Limit: 2})
+	c.Assert(err, IsNil)
+	c.Assert(queries, DeepEquals, []string{"limit=2&status=won"})
+
+	_, _, err = client.Dispute.ListByStatus("open")
+	c.Assert(err, NotNil)
+	_, _, err = client.Dispute.ListByStatusWith(Won, &ListOptions{Status: []string{Lost}})
+	c.Assert(err, NotNil)
+	_, _, err = client.Dispute.ListByStatus(Won, map[string]interface{}{"status[in]": "won,lost"})
+	c.Assert(err, NotNil)
+	c.Assert(queries, HasLen, 1)
+}
+
+func (s *DisputeSuite) TestFetchTransaction(c *C) {
+	dispute := mustFetchAnyDispute(c)
+	debit, _, err := sharedClient.Dispute.FetchTransaction(dispute)
+	c.Assert(err, IsNil)
+	c.Assert(debit.Id, Equals, dispute.Links.Transaction)
+}
+
+func (s *DisputeSuite) TestListPendingByDeadline(c *C) {
+	disputes, err := sharedClient.Dispute.ListPendingByDeadline()
+	c.Assert(err, IsNil)
+	for i := 1; i < len(disputes); i++ {
+		if disputes[i].RespondBy != nil {
+			c.Assert(disputes[i].RespondBy.Before(*disputes[i-1].RespondBy), Equals, false)
+		}
+	}
+}
+
+func (s *DisputeSuite) TestRespondBy(c *C) {
+	now := time.Now()
+	respondBy := now.Add(48 * time.Hour)
+	dispute := &Dispute{Status: Pending, RespondBy: &respondBy}
+	c.Assert(dispute.NeedsResponse(now), Equals, true)
+	c.Assert(dispute.IsOverdue(now), Equals, false)
+	c.Assert(dispute.TimeToRespond(now), Equals, 48*time.Hour)
+
+	later := now.Add(72 * time.Hour)
+	c.Assert(dispute.NeedsResponse(later), Equals, false)
+	c.Assert(dispute.IsOverdue(later), Equals, true)
+	c.Assert(dispute.TimeToRespond(later), Equals, -24*time.Hour)
+
+	dispute.Status = Won
+	c.Assert(dispute.NeedsResponse(now), Equals, false)
+	c.Assert(dispute.IsOverdue(later), Equals, false)
 }
 
 type OrderSuite struct{}
@@ -1415,6 +1673,48 @@ func (s *OrderSuite) TestUpdate(c *C) {
 	c.Assert(updatedOrder.Meta["xxx"], Equals, "yyy")
 }
 
+func (s *OrderSuite) TestDebitAndCredit(c *C) {
+	account := mustCreateBankAccount(sharedClient, nil)
+	defer deleteBankAccount(sharedClient, account, c)
+
+	customer := mustCreateCustomer(sharedClient)
+	// Can't delete a customer associated with an order
+	// defer deleteCustomer(sharedClient, customer.Id, c)
+
+	sharedClient.BankAccount.AssociateWithCustomer(account.Id, customer.Id)
+
+	order, _, err := sharedClient.Order.Create(customer.Id, &Order{
+		Description: "TestDebitAndCredit",
+	})
+	c.Assert(err, IsNil)
+
+	card := mustCreateCard(sharedClient)
+	debit, _, err := sharedClient.Order.DebitCard(order.Id, card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+	c.Assert(debit.Amount, Equals, 100)
+
+	_, _, err = sharedClient.Order.CreditBankAccount(order.Id, account.Id, &Credit{Amount: 150})
+	c.Assert(errors.Is(err, ErrInsufficientEscrow), Equals, true)
+
+	credit, _, err := sharedClient.Order.CreditBankAccount(order.Id, account.Id, &Credit{Amount: 40})
+	c.Assert(err, IsNil)
+	c.Assert(credit.Amount, Equals, 40)
+
+	debitPage, _, err := sharedClient.Order.ListDebits(order.Id)
+	c.Assert(err, IsNil)
+	c.Assert(debitPage.Total, Equals, 1)
+	c.Assert(debitPage.Debits[0].Id, Equals, debit.Id)
+
+	creditPage, _, err := sharedClient.Order.ListCredits(order.Id)
+	c.Assert(err, IsNil)
+	c.Assert(creditPage.Total, Equals, 1)
+	c.Assert(creditPage.Credits[0].Id, Equals, credit.Id)
+
+	refundPage, _, err := sharedClient.Order.ListRefunds(order.Id)
+	c.Assert(err, IsNil)
+	c.Assert(refundPage.Total, Equals, 0)
+}
+
 type ReversalSuite struct{}
 
 var _ = Suite(&ReversalSuite{})
@@ -1542,3 +1842,1344 @@ func (s *EventSuite) TestFetch(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(fetchedEvent.Id, Equals, event.Id)
 }
+
+func (s *EventSuite) TestAccessors(c *C) {
+	event := &Event{
+		Id:     "EV1",
+		Type:   EventDebitSucceeded,
+		Entity: &EventEntity{Debits: []Debit{{Id: "WD1", Amount: 100}}},
+	}
+	debit, err := event.Debit()
+	c.Assert(err, IsNil)
+	c.Assert(debit.Id, Equals, "WD1")
+
+	_, err = event.Dispute()
+	c.Assert(errors.Is(err, ErrEventTypeMismatch), Equals, true)
+
+	// card_hold.* events are not about cards.
+	_, err = (&Event{Id: "EV2", Type: EventCardHoldCreated}).Card()
+	c.Assert(errors.Is(err, ErrEventTypeMismatch), Equals, true)
+
+	_, err = (&Event{Id: "EV3", Type: EventDisputeCreated}).Dispute()
+	c.Assert(err, NotNil)
+	c.Assert(errors.Is(err, ErrEventTypeMismatch), Equals, false)
+}
+
+type TransactionSuite struct{}
+
+var _ = Suite(&TransactionSuite{})
+
+func (s *TransactionSuite) TestListForCustomer(c *C) {
+	card := mustCreateCardFixture(sharedClient, "VisaSuccess")
+	defer deleteCard(sharedClient, card, c)
+
+	customer := mustCreateCustomer(sharedClient)
+	_, _, err := sharedClient.Customer.AssociateWithCard(customer.Id, card.Id)
+	c.Assert(err, IsNil)
+
+	debit, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+	refund, _, err := sharedClient.Debit.Refund(debit.Id, &Refund{Amount: 40})
+	c.Assert(err, IsNil)
+
+	transactionPage, _, err := sharedClient.Customer.ListTransactions(customer.Id)
+	c.Assert(err, IsNil)
+	c.Assert(len(transactionPage.Transactions), Equals, 2)
+	c.Assert(transactionPage.Transactions[0].Kind(), Equals, TransactionRefund)
+	c.Assert(transactionPage.Transactions[0].Id(), Equals, refund.Id)
+	c.Assert(transactionPage.Transactions[1].Debit.Id, Equals, debit.Id)
+}
+
+func (s *TransactionSuite) TestFetch(c *C) {
+	card := mustCreateCardFixture(sharedClient, "VisaSuccess")
+	defer deleteCard(sharedClient, card, c)
+
+	debit, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	transaction, _, err := sharedClient.Transaction.Fetch(debit.Id)
+	c.Assert(err, IsNil)
+	switch v := transaction.Value().(type) {
+	case *Debit:
+		c.Assert(v.Id, Equals, debit.Id)
+	default:
+		c.Fatalf("Expected a debit, got %T", v)
+	}
+
+	_, _, err = sharedClient.Transaction.Fetch("XX123")
+	c.Assert(err, NotNil)
+}
+
+func (s *TransactionSuite) TestList(c *C) {
+	transactionPage, _, err := sharedClient.Transaction.List(&ListOptions{Limit: 5})
+	c.Assert(err, IsNil)
+	c.Assert(len(transactionPage.Transactions) <= 5, Equals, true)
+}
+
+func (s *TransactionSuite) TestMergeOrder(c *C) {
+	t0 := time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1, t2 := t0.Add(time.Hour), t0.Add(2*time.Hour)
+	res := &transactionResponse{
+		Debits:    []Debit{{Id: "WD1", CreatedAt: &t0}},
+		Credits:   []Credit{{Id: "CR1", CreatedAt: &t2}},
+		CardHolds: []CardHold{{Id: "HL1", CreatedAt: &t1}},
+	}
+	ids := func(transactions []Transaction) []string {
+		var ids []string
+		for _, transaction := range transactions {
+			ids = append(ids, transaction.Id())
+		}
+		return ids
+	}
+	c.Assert(ids(res.transactions("/transactions", nil)), DeepEquals, []string{"CR1", "HL1", "WD1"})
+
+	ascending := map[string]interface{}{"sort": "created_at,asc"}
+	c.Assert(ids(res.transactions("/transactions", ascending)), DeepEquals, []string{"WD1", "HL1", "CR1"})
+	// The following pages carry the sort in their href.
+	c.Assert(ids(res.transactions("/transactions?offset=10&sort=created_at%2Casc", nil)),
+		DeepEquals, []string{"WD1", "HL1", "CR1"})
+}
+
+type LinkSuite struct{}
+
+var _ = Suite(&LinkSuite{})
+
+func (s *LinkSuite) TestExpandLink(c *C) {
+	debit := &Debit{Id: "WD1", Links: &DebitLinks{Source: "CC1"}}
+
+	href, err := ExpandLink("/resources/{debits.source}", debit)
+	c.Assert(err, IsNil)
+	c.Assert(href, Equals, "/resources/CC1")
+
+	href, err = ExpandLink("/debits/{debits.self}/refunds", debit)
+	c.Assert(err, IsNil)
+	c.Assert(href, Equals, "/debits/WD1/refunds")
+
+	_, err = ExpandLink("/orders/{debits.order}", debit)
+	c.Assert(err, NotNil)
+
+	_, err = ExpandLink("/resources/{debits.source}", &Debit{})
+	c.Assert(err, NotNil)
+}
+
+func (s *LinkSuite) TestTemplateForEveryResponseLink(c *C) {
+	registered := map[string]bool{}
+	for _, links := range responseLinkTypes {
+		registered[reflect.TypeOf(links).Name()] = true
+	}
+
+	// Every *ResponseLinks type of the package must be registered, with a
+	// template for each of its links.
+	packages, err := parser.ParseDir(token.NewFileSet(), ".", nil, 0)
+	c.Assert(err, IsNil)
+	found := 0
+	for _, file := range packages["balanced"].Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok || !strings.HasSuffix(spec.Name.Name, "ResponseLinks") {
+				return true
+			}
+			found++
+			c.Check(registered[spec.Name.Name], Equals, true, Commentf("%v", spec.Name.Name))
+			return false
+		})
+	}
+	c.Assert(found, Equals, len(responseLinkTypes))
+	for rel, template := range linkTemplates {
+		c.Check(strings.HasPrefix(template, "/"), Equals, true, Commentf("%v", rel))
+	}
+}
+
+func (s *LinkSuite) TestFollowLink(c *C) {
+	card := mustCreateCardFixture(sharedClient, "VisaSuccess")
+	defer deleteCard(sharedClient, card, c)
+
+	debit, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	source, _, err := sharedClient.FollowLink("debits.source", debit)
+	c.Assert(err, IsNil)
+	c.Assert(source.(*Card).Id, Equals, card.Id)
+
+	refunds, _, err := sharedClient.FollowLink("debits.refunds", debit)
+	c.Assert(err, IsNil)
+	c.Assert(refunds.(*RefundPage).Total, Equals, 0)
+}
+
+func (s *LinkSuite) TestFetchHref(c *C) {
+	card := mustCreateCardFixture(sharedClient, "VisaSuccess")
+	defer deleteCard(sharedClient, card, c)
+
+	debit, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	resource, _, err := sharedClient.FetchHref(debit.Href)
+	c.Assert(err, IsNil)
+	c.Assert(resource.(*Debit).Id, Equals, debit.Id)
+
+	resource, _, err = sharedClient.FetchHref("/cards/" + card.Id + "/debits")
+	c.Assert(err, IsNil)
+	c.Assert(resource.(*DebitPage).Debits[0].Id, Equals, debit.Id)
+}
+
+func (s *LinkSuite) TestFetchHrefOnAnotherHost(c *C) {
+	var authorizations []string
+	record := func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"debits":[{"id":"WD1"}]}`))
+	}
+	api := httptest.NewServer(http.HandlerFunc(record))
+	defer api.Close()
+	other := httptest.NewServer(http.HandlerFunc(record))
+	defer other.Close()
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(api.URL))
+
+	_, _, err := client.FetchHref(other.URL + "/debits/WD1")
+	c.Assert(errors.Is(err, ErrForeignUrl), Equals, true)
+	c.Assert(authorizations, HasLen, 0)
+
+	resource, _, err := client.FetchHref(api.URL + "/debits/WD1")
+	c.Assert(err, IsNil)
+	c.Assert(resource.(*Debit).Id, Equals, "WD1")
+	c.Assert(authorizations, HasLen, 1)
+	c.Assert(authorizations[0], Not(Equals), "")
+}
+
+type FundingInstrumentSuite struct{}
+
+var _ = Suite(&FundingInstrumentSuite{})
+
+func (s *FundingInstrumentSuite) TestDebitAndFetchSource(c *C) {
+	card := mustCreateCardFixture(sharedClient, "VisaSuccess")
+	defer deleteCard(sharedClient, card, c)
+
+	debit, _, err := sharedClient.FundingInstrument.Debit(card, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+	c.Assert(debit.Amount, Equals, 100)
+
+	source, _, err := sharedClient.Debit.FetchSource(debit)
+	c.Assert(err, IsNil)
+	c.Assert(source.InstrumentId(), Equals, card.Id)
+	_, isCard := source.(*Card)
+	c.Assert(isCard, Equals, true)
+}
+
+func (s *FundingInstrumentSuite) TestCreditAndFetchDestination(c *C) {
+	card := mustCreateCardFixture(sharedClient, "VisaSuccess")
+	defer deleteCard(sharedClient, card, c)
+	_, _, err := sharedClient.FundingInstrument.Debit(card, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	account := mustCreateBankAccount(sharedClient, nil)
+	defer deleteBankAccount(sharedClient, account, c)
+
+	credit, _, err := sharedClient.FundingInstrument.Credit(account, &Credit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	destination, _, err := sharedClient.Credit.FetchDestination(credit)
+	c.Assert(err, IsNil)
+	c.Assert(destination.(*BankAccount).Id, Equals, account.Id)
+}
+
+func (s *FundingInstrumentSuite) TestAssociateWithCustomer(c *C) {
+	account := mustCreateBankAccount(sharedClient, nil)
+	defer deleteBankAccount(sharedClient, account, c)
+
+	customer := mustCreateCustomer(sharedClient)
+	defer deleteCustomer(sharedClient, customer.Id, c)
+
+	instrument, _, err := sharedClient.FundingInstrument.AssociateWithCustomer(account, customer.Id)
+	c.Assert(err, IsNil)
+	c.Assert(instrument.(*BankAccount).Links.Customer, Equals, customer.Id)
+}
+
+type ContextSuite struct{}
+
+var _ = Suite(&ContextSuite{})
+
+func (s *ContextSuite) TestCanceled(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cardPage, _, err := sharedClient.Card.ListContext(ctx)
+	c.Assert(err, ErrorMatches, ".*context canceled")
+	c.Assert(cardPage, IsNil)
+}
+
+func (s *ContextSuite) TestDeadlineExceeded(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+	card, _, err := sharedClient.Card.CreateContext(ctx, cardFixtures["VisaSuccess"])
+	c.Assert(err, ErrorMatches, ".*context deadline exceeded")
+	c.Assert(card, IsNil)
+}
+
+func (s *ContextSuite) TestBackground(c *C) {
+	card, _, err := sharedClient.Card.CreateContext(context.Background(), cardFixtures["VisaSuccess"])
+	c.Assert(err, IsNil)
+	defer deleteCard(sharedClient, card, c)
+
+	fetchedCard, _, err := sharedClient.Card.FetchContext(context.Background(), card.Id)
+	c.Assert(err, IsNil)
+	c.Assert(fetchedCard.Id, Equals, card.Id)
+}
+
+type RetrySuite struct{}
+
+var _ = Suite(&RetrySuite{})
+
+func (s *RetrySuite) TestBackoff(c *C) {
+	policy := &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+	c.Assert(policy.backoff(1, nil), Equals, 100*time.Millisecond)
+	c.Assert(policy.backoff(2, nil), Equals, 200*time.Millisecond)
+	c.Assert(policy.backoff(3, nil), Equals, 400*time.Millisecond)
+	c.Assert(policy.backoff(5, nil), Equals, time.Second)
+
+	policy.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		d := policy.backoff(2, nil)
+		c.Assert(d >= 100*time.Millisecond && d <= 200*time.Millisecond, Equals, true)
+	}
+}
+
+func (s *RetrySuite) TestOnlySafeRequestsAreRetried(c *C) {
+	policy := DefaultRetryPolicy()
+	getReq, err := sharedClient.NewRequest("GET", "/cards", nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(policy.shouldRetry(getReq, nil, fmt.Errorf("connection reset"), 1), Equals, true)
+	c.Assert(policy.shouldRetry(getReq, nil, fmt.Errorf("connection reset"), 3), Equals, false)
+
+	postReq, err := sharedClient.NewRequest("POST", "/cards", nil, cardFixtures["VisaSuccess"])
+	c.Assert(err, IsNil)
+	c.Assert(policy.shouldRetry(postReq, nil, fmt.Errorf("connection reset"), 1), Equals, false)
+
+	// The API may have carried out a POST that ended with a transport error,
+	// so it is retried only if the policy trusts the idempotency key.
+	postReq.Header.Set(IdempotencyKeyHeader, "TestOnlySafeRequestsAreRetried")
+	c.Assert(policy.shouldRetry(postReq, nil, fmt.Errorf("connection reset"), 1), Equals, false)
+	policy.RetryTransportErrors = true
+	c.Assert(policy.shouldRetry(postReq, nil, fmt.Errorf("connection reset"), 1), Equals, true)
+}
+
+func (s *RetrySuite) TestFetchWithRetryPolicy(c *C) {
+	client := NewClient(nil, sharedClient.secret)
+	client.RetryPolicy = DefaultRetryPolicy()
+	card := mustCreateCard(client)
+	defer deleteCard(client, card, c)
+
+	fetchedCard, _, err := client.Card.Fetch(card.Id)
+	c.Assert(err, IsNil)
+	c.Assert(fetchedCard.Id, Equals, card.Id)
+}
+
+func (s *RetrySuite) TestRetryLoop(c *C) {
+	attempts := map[string]int{}
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+		attempts[request]++
+		if r.Method == "POST" && r.URL.Path == "/cards/CC1/debits" {
+			keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		}
+		if attempts[request] < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"errors": [{"status_code": 503, "description": "Try again"}]}`))
+			return
+		}
+		switch request {
+		case "GET /cards/CC1":
+			w.Write([]byte(`{"cards": [{"id": "CC1"}]}`))
+		case "POST /cards/CC1/debits":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"debits": [{"id": "WD1", "amount": 100}]}`))
+		}
+	}))
+	defer server.Close()
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff, policy.MaxBackoff = time.Millisecond, time.Millisecond
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(server.URL), WithRetryPolicy(policy))
+
+	// A GET is retried on 503 until it succeeds.
+	card, _, err := client.Card.Fetch("CC1")
+	c.Assert(err, IsNil)
+	c.Assert(card.Id, Equals, "CC1")
+	c.Assert(attempts["GET /cards/CC1"], Equals, 3)
+
+	// A POST without an idempotency key is attempted once.
+	_, _, err = client.Customer.Create(&Customer{Name: "Jane Doe"})
+	c.Assert(errors.Is(err, ErrServer), Equals, true)
+	c.Assert(attempts["POST /customers"], Equals, 1)
+
+	// A POST with an idempotency key is retried with the same key.
+	debit, _, err := client.Card.Charge("CC1", &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+	c.Assert(debit.Id, Equals, "WD1")
+	c.Assert(keys, HasLen, 3)
+	c.Assert(keys[0], Not(Equals), "")
+	c.Assert(keys[1], Equals, keys[0])
+	c.Assert(keys[2], Equals, keys[0])
+}
+
+func (s *RetrySuite) TestRetryLoopAfterDroppedConnection(c *C) {
+	var charges int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&charges, 1) == 1 {
+			// Drop the connection after reading the request.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			c.Assert(err, IsNil)
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"debits": [{"id": "WD1", "amount": 100}]}`))
+	}))
+	defer server.Close()
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff, policy.MaxBackoff = time.Millisecond, time.Millisecond
+	httpClient := &http.Client{Transport: &http.Transport{}}
+	client := NewClient(httpClient, "not-a-secret", WithBaseUrl(server.URL), WithRetryPolicy(policy))
+
+	// By default, a charge whose connection dropped is not retried.
+	_, _, err := client.Card.Charge("CC1", &Debit{Amount: 100})
+	c.Assert(err, NotNil)
+	c.Assert(atomic.LoadInt32(&charges), Equals, int32(1))
+
+	atomic.StoreInt32(&charges, 0)
+	policy.RetryTransportErrors = true
+	debit, _, err := client.Card.Charge("CC1", &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+	c.Assert(debit.Id, Equals, "WD1")
+	c.Assert(atomic.LoadInt32(&charges), Equals, int32(2))
+}
+
+type IdempotencySuite struct{}
+
+var _ = Suite(&IdempotencySuite{})
+
+func (s *IdempotencySuite) TestChargeReplaysWithSameKey(c *C) {
+	card := mustCreateCard(sharedClient)
+	defer deleteCard(sharedClient, card, c)
+	sharedClient.IdempotencyCache = NewIdempotencyCache(time.Hour, 10)
+	defer func() { sharedClient.IdempotencyCache = nil }()
+
+	ctx := WithIdempotencyKey(context.Background(), NewIdempotencyKey())
+	debit, _, err := sharedClient.Card.ChargeContext(ctx, card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	replayedDebit, _, err := sharedClient.Card.ChargeContext(ctx, card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+	c.Assert(replayedDebit.Id, Equals, debit.Id)
+}
+
+func (s *IdempotencySuite) TestChargeWithoutKey(c *C) {
+	card := mustCreateCard(sharedClient)
+	defer deleteCard(sharedClient, card, c)
+
+	debit, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	otherDebit, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+	c.Assert(otherDebit.Id, Not(Equals), debit.Id)
+}
+
+func (s *IdempotencySuite) TestCacheRemembersCallerKeys(c *C) {
+	var charges int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		charges++
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"debits": [{"id": "WD%d", "amount": 100}]}`, charges)
+	}))
+	defer server.Close()
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(server.URL))
+
+	// Without a cache, every call is sent.
+	ctx := WithIdempotencyKey(context.Background(), NewIdempotencyKey())
+	client.Card.ChargeContext(ctx, "CC1", &Debit{Amount: 100})
+	client.Card.ChargeContext(ctx, "CC1", &Debit{Amount: 100})
+	c.Assert(charges, Equals, 2)
+
+	// Responses to calls with a generated key are not remembered.
+	client.IdempotencyCache = NewIdempotencyCache(time.Hour, 10)
+	client.Card.Charge("CC1", &Debit{Amount: 100})
+	c.Assert(client.IdempotencyCache.records, HasLen, 0)
+
+	// A call repeated with a key chosen by the caller is replayed.
+	debit, _, err := client.Card.ChargeContext(ctx, "CC1", &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+	replayedDebit, _, err := client.Card.ChargeContext(ctx, "CC1", &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+	c.Assert(replayedDebit.Id, Equals, debit.Id)
+	c.Assert(charges, Equals, 4)
+}
+
+func (s *IdempotencySuite) TestNewIdempotencyKey(c *C) {
+	key := NewIdempotencyKey()
+	c.Assert(key, HasLen, 32)
+	c.Assert(NewIdempotencyKey(), Not(Equals), key)
+
+	_, ok := IdempotencyKeyFromContext(context.Background())
+	c.Assert(ok, Equals, false)
+	fromCtx, ok := IdempotencyKeyFromContext(WithIdempotencyKey(context.Background(), key))
+	c.Assert(ok, Equals, true)
+	c.Assert(fromCtx, Equals, key)
+}
+
+type ClientOptionSuite struct{}
+
+var _ = Suite(&ClientOptionSuite{})
+
+func (s *ClientOptionSuite) TestDefaults(c *C) {
+	req, err := NewClient(nil, "").NewRequest("GET", "/cards", nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(req.URL.String(), Equals, "https://api.balancedpayments.com/cards")
+	c.Assert(req.Header.Get("Accept"), Equals, "application/vnd.api+json;revision=1.1")
+}
+
+func (s *ClientOptionSuite) TestWithBaseUrl(c *C) {
+	client := NewClient(nil, "", WithBaseUrl("http://localhost:8080/balanced/"))
+	req, err := client.NewRequest("GET", "/cards/CC123", nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(req.URL.String(), Equals, "http://localhost:8080/balanced/cards/CC123")
+
+	client = NewClient(nil, "", WithBaseUrl("://"))
+	_, err = client.NewRequest("GET", "/cards", nil, nil)
+	c.Assert(err, NotNil)
+}
+
+func (s *ClientOptionSuite) TestWithApiRevision(c *C) {
+	client := NewClient(nil, "", WithApiRevision("1.0"))
+	req, err := client.NewRequest("GET", "/cards", nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(req.Header.Get("Accept"), Equals, "application/vnd.api+json;revision=1.0")
+}
+
+func (s *ClientOptionSuite) TestRequestAgainstBaseUrl(c *C) {
+	client := NewClient(nil, sharedClient.secret, WithBaseUrl(baseUrlStr))
+	cardPage, _, err := client.Card.List()
+	c.Assert(err, IsNil)
+	c.Assert(cardPage, NotNil)
+}
+
+func (s *ClientOptionSuite) TestWithUserAgentAndHeader(c *C) {
+	client := NewClient(nil, "", WithUserAgent("myapp/2.0"), WithHeader("X-Request-Source", "tests"))
+	req, err := client.NewRequest("GET", "/cards", nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(req.Header.Get("User-Agent"), Equals, "balanced-go/1.1 myapp/2.0")
+	c.Assert(req.Header.Get("X-Request-Source"), Equals, "tests")
+
+	// The headers the client sets itself are ignored, even when it has no
+	// secret or the request no body.
+	client = NewClient(nil, "", WithHeader("authorization", "Basic Zm9vOg=="), WithHeader("Content-Type", "text/plain"))
+	req, err = client.NewRequest("GET", "/cards", nil, nil)
+	c.Assert(err, IsNil)
+	c.Assert(req.Header.Get("Authorization"), Equals, "")
+	c.Assert(req.Header.Get("Content-Type"), Equals, "")
+}
+
+func (s *ClientOptionSuite) TestWithTimeout(c *C) {
+	client := NewClient(nil, sharedClient.secret, WithTimeout(time.Nanosecond))
+	_, _, err := client.Card.List()
+	c.Assert(err, ErrorMatches, ".*context deadline exceeded.*")
+}
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func (s *ClientOptionSuite) TestWithLoggerAndTransport(c *C) {
+	logger := new(testLogger)
+	client := NewClient(nil, sharedClient.secret,
+		WithLogger(logger),
+		WithTransport(http.DefaultTransport),
+		WithRetryPolicy(DefaultRetryPolicy()))
+	c.Assert(client.RetryPolicy, NotNil)
+
+	_, _, err := client.Card.List()
+	c.Assert(err, IsNil)
+	c.Assert(logger.lines, HasLen, 1)
+	c.Assert(logger.lines[0], Matches, "balanced: GET https://api.balancedpayments.com/cards: 200 .*")
+}
+
+type ErrorSuite struct{}
+
+var _ = Suite(&ErrorSuite{})
+
+func (s *ErrorSuite) TestNotFound(c *C) {
+	card, _, err := sharedClient.Card.Fetch("CCdoesnotexist")
+	c.Assert(card, IsNil)
+	c.Assert(errors.Is(err, ErrNotFound), Equals, true)
+	c.Assert(errors.Is(err, ErrCardDeclined), Equals, false)
+
+	var errorResponse *ErrorResponse
+	c.Assert(errors.As(err, &errorResponse), Equals, true)
+	c.Assert(errorResponse.RequestId, Not(Equals), "")
+}
+
+func (s *ErrorSuite) TestUnauthorized(c *C) {
+	client := NewClient(nil, "not-a-secret")
+	_, _, err := client.Card.List()
+	c.Assert(errors.Is(err, ErrUnauthorized), Equals, true)
+}
+
+func (s *ErrorSuite) TestValidation(c *C) {
+	card := mustCreateCard(sharedClient)
+	defer deleteCard(sharedClient, card, c)
+
+	_, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: -1})
+	c.Assert(errors.Is(err, ErrValidation), Equals, true)
+
+	var validationErr *ValidationError
+	c.Assert(errors.As(err, &validationErr), Equals, true)
+	c.Assert(validationErr.Fields, Not(HasLen), 0)
+}
+
+func (s *ErrorSuite) TestVerificationFailed(c *C) {
+	account := mustCreateBankAccount(sharedClient, nil)
+	defer deleteBankAccount(sharedClient, account, c)
+
+	verif, _, err := sharedClient.Verification.Create(account.Id)
+	c.Assert(err, IsNil)
+	_, _, err = sharedClient.Verification.Confirm(verif.Id, 2, 2)
+	c.Assert(errors.Is(err, ErrVerificationFailed), Equals, true)
+}
+
+func (s *ErrorSuite) TestErrorMatching(c *C) {
+	res := &http.Response{StatusCode: 402, Request: &http.Request{Method: "POST"}}
+	err := &ErrorResponse{
+		Response: res,
+		Errors: []ErrorResponseError{
+			{CategoryCode: "card-declined", Description: "Declined"},
+			{CategoryCode: "insufficient-funds", Description: "Not enough"},
+		},
+	}
+	c.Assert(errors.Is(err, ErrCardDeclined), Equals, true)
+	c.Assert(errors.Is(err, ErrInsufficientFunds), Equals, true)
+	c.Assert(errors.Is(err, ErrServer), Equals, false)
+
+	var e *ErrorResponseError
+	c.Assert(errors.As(err, &e), Equals, true)
+	c.Assert(e.CategoryCode, Equals, "card-declined")
+}
+
+func newErrorHttpResponse(code int, body string) *http.Response {
+	req, _ := http.NewRequest("GET", "https://api.balancedpayments.com/cards", nil)
+	return &http.Response{
+		StatusCode: code,
+		Header:     http.Header{"Content-Type": {"text/html"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func (s *ErrorSuite) TestNonJsonBody(c *C) {
+	err := checkResponse(newErrorHttpResponse(502, "<html><body>Bad Gateway</body></html>"))
+	errorResponse, ok := err.(*ErrorResponse)
+	c.Assert(ok, Equals, true)
+	c.Assert(errorResponse.StatusCode, Equals, 502)
+	c.Assert(errorResponse.Header.Get("Content-Type"), Equals, "text/html")
+	c.Assert(errorResponse.RawBody, Equals, "<html><body>Bad Gateway</body></html>")
+	c.Assert(errorResponse.Errors, HasLen, 0)
+	c.Assert(err, ErrorMatches, "GET https://api.balancedpayments.com/cards: 502 Bad Gateway: <html>.*")
+	c.Assert(errors.Is(err, ErrServer), Equals, true)
+}
+
+func (s *ErrorSuite) TestEmptyBody(c *C) {
+	err := checkResponse(newErrorHttpResponse(503, ""))
+	c.Assert(err, ErrorMatches, "GET https://api.balancedpayments.com/cards: 503 Service Unavailable")
+}
+
+func (s *ErrorSuite) TestRawBodyIsTruncated(c *C) {
+	err := checkResponse(newErrorHttpResponse(500, strings.Repeat("x", 2*maxErrorBodyLen)))
+	c.Assert(err.(*ErrorResponse).RawBody, HasLen, maxErrorBodyLen)
+}
+
+func (s *ErrorSuite) TestLargeBodyIsNotReadFully(c *C) {
+	res := newErrorHttpResponse(502, strings.Repeat("x", 4*maxErrorBodyRead))
+	body := res.Body
+	err := checkResponse(res)
+	c.Assert(err.(*ErrorResponse).RawBody, HasLen, maxErrorBodyLen)
+
+	unread, _ := ioutil.ReadAll(body)
+	c.Assert(unread, HasLen, 3*maxErrorBodyRead)
+	read, _ := ioutil.ReadAll(res.Body)
+	c.Assert(read, HasLen, maxErrorBodyRead)
+}
+
+type IteratorSuite struct{}
+
+var _ = Suite(&IteratorSuite{})
+
+func (s *IteratorSuite) TestFollowsNextLinks(c *C) {
+	for i := 0; i < 3; i++ {
+		card := mustCreateCard(sharedClient)
+		defer deleteCard(sharedClient, card, c)
+	}
+	cardPage, _, err := sharedClient.Card.List()
+	c.Assert(err, IsNil)
+
+	count := 0
+	it := sharedClient.Card.Iter(0, 2)
+	for it.Next() {
+		c.Assert(it.Value().Id, Not(Equals), "")
+		count++
+	}
+	c.Assert(it.Err(), IsNil)
+	c.Assert(count, Equals, cardPage.Total)
+}
+
+func (s *IteratorSuite) TestRangeOverFunc(c *C) {
+	for i := 0; i < 3; i++ {
+		card := mustCreateCard(sharedClient)
+		defer deleteCard(sharedClient, card, c)
+	}
+
+	count := 0
+	for card, err := range sharedClient.Card.Iter(0, 1).All() {
+		c.Assert(err, IsNil)
+		c.Assert(card.Id, Not(Equals), "")
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	c.Assert(count, Equals, 2)
+}
+
+// newPagedCardServer returns a server listing cards on three pages of two
+// linked by next links, failing the requests for the pages at the offsets in
+// failing. The path and query of each request are appended to requests.
+func newPagedCardServer(requests *[]string, failing ...string) *httptest.Server {
+	pages := map[string]string{
+		"0": `{"cards": [{"id": "CC1"}, {"id": "CC2"}],
+			"meta": {"next": "/cards?limit=2&offset=2"}}`,
+		"2": `{"cards": [{"id": "CC3"}, {"id": "CC4"}],
+			"meta": {"next": "/cards?limit=2&offset=4"}}`,
+		"4": `{"cards": [{"id": "CC5"}], "meta": {}}`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.Path+"?"+r.URL.RawQuery)
+		offset := r.URL.Query().Get("offset")
+		for _, page := range failing {
+			if offset == page {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"errors": [{"status_code": 500, "description": "Oops"}]}`))
+				return
+			}
+		}
+		w.Write([]byte(pages[offset]))
+	}))
+}
+
+func (s *IteratorSuite) TestFollowsNextLinksLocally(c *C) {
+	var requests []string
+	server := newPagedCardServer(&requests)
+	defer server.Close()
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(server.URL))
+
+	var ids []string
+	it := client.Card.Iter(0, 2)
+	for it.Next() {
+		ids = append(ids, it.Value().Id)
+	}
+	c.Assert(it.Err(), IsNil)
+	c.Assert(ids, DeepEquals, []string{"CC1", "CC2", "CC3", "CC4", "CC5"})
+	c.Assert(requests, DeepEquals, []string{
+		"/cards?limit=2&offset=0",
+		"/cards?limit=2&offset=2",
+		"/cards?limit=2&offset=4",
+	})
+}
+
+func (s *IteratorSuite) TestBreakStopsFetching(c *C) {
+	var requests []string
+	server := newPagedCardServer(&requests)
+	defer server.Close()
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(server.URL))
+
+	var ids []string
+	for card, err := range client.Card.Iter(0, 2).All() {
+		c.Assert(err, IsNil)
+		ids = append(ids, card.Id)
+		if len(ids) == 2 {
+			break
+		}
+	}
+	c.Assert(ids, DeepEquals, []string{"CC1", "CC2"})
+	c.Assert(requests, HasLen, 1)
+}
+
+func (s *IteratorSuite) TestError(c *C) {
+	var requests []string
+	server := newPagedCardServer(&requests, "2")
+	defer server.Close()
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(server.URL))
+
+	var ids []string
+	it := client.Card.Iter(0, 2)
+	for it.Next() {
+		ids = append(ids, it.Value().Id)
+	}
+	c.Assert(ids, DeepEquals, []string{"CC1", "CC2"})
+	c.Assert(errors.Is(it.Err(), ErrServer), Equals, true)
+	c.Assert(it.Next(), Equals, false)
+	c.Assert(requests, HasLen, 2)
+
+	// All yields the error once, last.
+	var errs []error
+	ids = nil
+	for card, err := range client.Card.Iter(0, 2).All() {
+		if err != nil {
+			c.Assert(card, IsNil)
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, card.Id)
+	}
+	c.Assert(ids, DeepEquals, []string{"CC1", "CC2"})
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errors.Is(errs[0], ErrServer), Equals, true)
+}
+
+type PaginationSuite struct{}
+
+var _ = Suite(&PaginationSuite{})
+
+func (s *PaginationSuite) TestNewPaginationParamsWithNulls(c *C) {
+	params := NewPaginationParams(map[string]interface{}{
+		"limit":    float64(10),
+		"offset":   nil,
+		"first":    "/cards?limit=10&offset=0",
+		"next":     nil,
+		"previous": "/cards?limit=10&offset=0",
+	})
+	c.Assert(params.Limit, Equals, 10)
+	c.Assert(params.Offset, Equals, 0)
+	c.Assert(params.Total, Equals, 0)
+	c.Assert(params.Last, Equals, "")
+	c.Assert(params.HasNext(), Equals, false)
+	c.Assert(params.HasPrevious(), Equals, true)
+}
+
+func (s *PaginationSuite) TestFetchPage(c *C) {
+	for i := 0; i < 2; i++ {
+		card := mustCreateCard(sharedClient)
+		defer deleteCard(sharedClient, card, c)
+	}
+
+	firstPage, _, err := sharedClient.Card.List(0, 1)
+	c.Assert(err, IsNil)
+	c.Assert(firstPage.HasNext(), Equals, true)
+	c.Assert(firstPage.HasPrevious(), Equals, false)
+
+	secondPage, _, err := sharedClient.Card.FetchPage(firstPage.Next)
+	c.Assert(err, IsNil)
+	c.Assert(secondPage.Offset, Equals, 1)
+	c.Assert(secondPage.Cards, HasLen, 1)
+	c.Assert(secondPage.Cards[0].Id, Not(Equals), firstPage.Cards[0].Id)
+
+	_, _, err = sharedClient.Card.FetchPage(firstPage.Previous)
+	c.Assert(err, Equals, ErrNoPage)
+}
+
+type ListOptionsSuite struct{}
+
+var _ = Suite(&ListOptionsSuite{})
+
+func (s *ListOptionsSuite) TestQuery(c *C) {
+	after := time.Date(2014, 6, 1, 0, 0, 0, 0, time.UTC)
+	query, err := paginatedArgsToQuery([]interface{}{&ListOptions{
+		Limit:        10,
+		Offset:       20,
+		Sort:         "created_at,desc",
+		CreatedAfter: &after,
+		Status:       []string{Succeeded},
+		Meta:         map[string]string{"order_number": "123"},
+		Filters:      map[string]string{"amount[>]": "500"},
+	}})
+	c.Assert(err, IsNil)
+	c.Assert(query, DeepEquals, map[string]interface{}{
+		"limit":              10,
+		"offset":             20,
+		"sort":               "created_at,desc",
+		"created_at[>]":      "2014-06-01T00:00:00Z",
+		"status":             Succeeded,
+		"meta[order_number]": "123",
+		"amount[>]":          "500",
+	})
+
+	query, err = paginatedArgsToQuery([]interface{}{ListOptions{Status: []string{Pending, Failed}}})
+	c.Assert(err, IsNil)
+	c.Assert(query["status[in]"], Equals, "pending,failed")
+}
+
+func (s *ListOptionsSuite) TestValidation(c *C) {
+	_, err := paginatedArgsToQuery([]interface{}{&ListOptions{Limit: -1}})
+	c.Assert(err, ErrorMatches, "balanced: invalid list options: negative limit -1")
+
+	_, err = paginatedArgsToQuery([]interface{}{&ListOptions{Sort: "created_at,sideways"}})
+	c.Assert(err, ErrorMatches, "balanced: invalid list options: sort .*")
+
+	_, err = paginatedArgsToQuery([]interface{}{&ListOptions{Filters: map[string]string{"offset": "1"}}})
+	c.Assert(err, ErrorMatches, "balanced: invalid list options: filter on \"offset\" conflicts with Offset")
+
+	_, err = paginatedArgsToQuery([]interface{}{&ListOptions{Filters: map[string]string{"created_at[>]": "2015-01-02"}}})
+	c.Assert(err, ErrorMatches, `balanced: invalid list options: filter on "created_at\[>\]" conflicts .*`)
+
+	_, err = paginatedArgsToQuery([]interface{}{&ListOptions{Filters: map[string]string{"meta[order_id]": "1"}}})
+	c.Assert(err, ErrorMatches, `balanced: invalid list options: filter on "meta\[order_id\]" conflicts with Meta`)
+
+	_, err = paginatedArgsToQuery([]interface{}{"limit=10"})
+	c.Assert(err, ErrorMatches, "balanced: unexpected list argument of type string")
+}
+
+func (s *ListOptionsSuite) TestListWithOptions(c *C) {
+	card := mustCreateCard(sharedClient)
+	defer deleteCard(sharedClient, card, c)
+	_, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	debitPage, _, err := sharedClient.Debit.ListWith(&ListOptions{
+		Limit:  1,
+		Sort:   "created_at,desc",
+		Status: []string{Succeeded},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(debitPage.Debits, HasLen, 1)
+	c.Assert(debitPage.Debits[0].Status, Equals, Succeeded)
+
+	_, _, err = sharedClient.Debit.ListWith(&ListOptions{Limit: -1})
+	c.Assert(err, NotNil)
+}
+
+func (s *ListOptionsSuite) TestTypedQuery(c *C) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Write([]byte(`{"cards": [], "meta": {"total": 0}}`))
+	}))
+	defer server.Close()
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(server.URL))
+
+	_, _, err := client.Card.ListWith(nil)
+	c.Assert(err, IsNil)
+	it := client.Customer.IterCardsWith("CU1", &ListOptions{Limit: 5, Offset: 10})
+	c.Assert(it.Next(), Equals, false)
+	c.Assert(it.Err(), IsNil)
+	c.Assert(queries, DeepEquals, []string{"/cards?", "/customers/CU1/cards?limit=5&offset=10"})
+}
+
+type MoneySuite struct{}
+
+var _ = Suite(&MoneySuite{})
+
+func (s *MoneySuite) TestString(c *C) {
+	c.Assert(USD(250000).String(), Equals, "$2,500.00")
+	c.Assert(USD(-5).String(), Equals, "-$0.05")
+	c.Assert(Money{Cents: 123456789, Currency: "eur"}.String(), Equals, "1,234,567.89 EUR")
+	c.Assert((&Debit{Amount: 1999}).Money().String(), Equals, "$19.99")
+	c.Assert((&Marketplace{UnsettledFees: 25}).UnsettledFeesMoney().String(), Equals, "$0.25")
+	c.Assert(Money{Cents: 500, Currency: "JPY"}.String(), Equals, "500 JPY")
+	c.Assert(Money{Cents: 1500, Currency: "KWD"}.String(), Equals, "1.500 KWD")
+}
+
+func (s *MoneySuite) TestParseMoney(c *C) {
+	money, err := ParseMoney("$2,500.00")
+	c.Assert(err, IsNil)
+	c.Assert(money, Equals, USD(250000))
+
+	money, err = ParseMoney("2500.5 EUR")
+	c.Assert(err, IsNil)
+	c.Assert(money, Equals, Money{Cents: 250050, Currency: "EUR"})
+
+	money, err = ParseMoney("1,500 JPY")
+	c.Assert(err, IsNil)
+	c.Assert(money, Equals, Money{Cents: 1500, Currency: "JPY"})
+
+	money, err = ParseMoney("1.5 KWD")
+	c.Assert(err, IsNil)
+	c.Assert(money, Equals, Money{Cents: 1500, Currency: "KWD"})
+
+	money, err = ParseMoney("-$92,233,720,368,547,758.07")
+	c.Assert(err, IsNil)
+	c.Assert(money, Equals, USD(-math.MaxInt64))
+
+	for _, invalid := range []string{"", "$", "2,50.00", "1.234", "$1 EUR", "1.5 JPY"} {
+		_, err = ParseMoney(invalid)
+		c.Assert(err, NotNil, Commentf("%q", invalid))
+	}
+
+	for _, huge := range []string{"$92,233,720,368,547,758.08", "$92,233,720,368,547,759", "9223372036854775808 JPY", "9,223,372,036,854,776 KWD"} {
+		_, err = ParseMoney(huge)
+		c.Assert(err, ErrorMatches, ".*: out of range", Commentf("%q", huge))
+	}
+}
+
+func (s *MoneySuite) TestArithmetic(c *C) {
+	sum, err := USD(150).Add(Money{Cents: 50})
+	c.Assert(err, IsNil)
+	c.Assert(sum, Equals, USD(200))
+
+	difference, err := USD(150).Sub(USD(200))
+	c.Assert(err, IsNil)
+	c.Assert(difference, Equals, USD(-50))
+
+	cmp, err := USD(150).Cmp(USD(200))
+	c.Assert(err, IsNil)
+	c.Assert(cmp, Equals, -1)
+
+	_, err = USD(150).Add(Money{Cents: 50, Currency: "EUR"})
+	c.Assert(errors.Is(err, ErrCurrencyMismatch), Equals, true)
+	_, err = USD(150).Cmp(Money{Cents: 50, Currency: "EUR"})
+	c.Assert(errors.Is(err, ErrCurrencyMismatch), Equals, true)
+}
+
+func (s *MoneySuite) TestJSON(c *C) {
+	var payment struct {
+		Amount   Money  `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	c.Assert(json.Unmarshal([]byte(`{"amount": 2500, "currency": "USD"}`), &payment), IsNil)
+	c.Assert(payment.Amount.Cents, Equals, int64(2500))
+
+	data, err := json.Marshal(payment)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `{"amount":2500,"currency":"USD"}`)
+}
+
+type WebhookSuite struct{}
+
+var _ = Suite(&WebhookSuite{})
+
+func serveWebhook(handler http.Handler, method, target, body string) int {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return recorder.Code
+}
+
+func (s *WebhookSuite) TestDispatch(c *C) {
+	var handled []string
+	webhooks := NewWebhookHandler()
+	webhooks.Handle("debit.succeeded", func(ctx context.Context, event *Event) error {
+		handled = append(handled, event.Id)
+		return nil
+	})
+
+	code := serveWebhook(webhooks, "POST", "/", `{"id": "EV1", "type": "debit.succeeded", "entity": {"debits": [{"amount": 100}]}}`)
+	c.Assert(code, Equals, http.StatusOK)
+
+	code = serveWebhook(webhooks, "PUT", "/", `{"events": [{"id": "EV2", "type": "debit.succeeded"}, {"id": "EV3", "type": "card.created"}]}`)
+	c.Assert(code, Equals, http.StatusOK)
+
+	// A GET callback carries no entity to handle without verification.
+	code = serveWebhook(webhooks, "GET", "/?id=EV4&type=debit.succeeded", "")
+	c.Assert(code, Equals, http.StatusBadRequest)
+
+	c.Assert(handled, DeepEquals, []string{"EV1", "EV2"})
+}
+
+func (s *WebhookSuite) TestGetCallbackIsFetched(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, Equals, "/events/EV4")
+		w.Write([]byte(`{"events": [{"id": "EV4", "type": "debit.succeeded", "entity": {"debits": [{"id": "WD1", "amount": 100}]}}]}`))
+	}))
+	defer server.Close()
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(server.URL))
+
+	var debits []string
+	webhooks := NewWebhookHandler(WithEventVerification(client))
+	webhooks.Handle("debit.succeeded", func(ctx context.Context, event *Event) error {
+		debit, err := event.Debit()
+		if err != nil {
+			return err
+		}
+		debits = append(debits, debit.Id)
+		return nil
+	})
+
+	code := serveWebhook(webhooks, "GET", "/?id=EV4&type=debit.succeeded", "")
+	c.Assert(code, Equals, http.StatusOK)
+	c.Assert(debits, DeepEquals, []string{"WD1"})
+}
+
+func (s *WebhookSuite) TestHandlerFailure(c *C) {
+	webhooks := NewWebhookHandler()
+	webhooks.Handle("dispute.created", func(ctx context.Context, event *Event) error {
+		return errors.New("database unavailable")
+	})
+	code := serveWebhook(webhooks, "POST", "/", `{"id": "EV1", "type": "dispute.created"}`)
+	c.Assert(code, Equals, http.StatusInternalServerError)
+}
+
+func (s *WebhookSuite) TestBadRequests(c *C) {
+	webhooks := NewWebhookHandler()
+	c.Assert(serveWebhook(webhooks, "POST", "/", "not json"), Equals, http.StatusBadRequest)
+	c.Assert(serveWebhook(webhooks, "POST", "/", `{"id": "EV1"}`), Equals, http.StatusBadRequest)
+	c.Assert(serveWebhook(webhooks, "GET", "/?id=EV1", ""), Equals, http.StatusBadRequest)
+	c.Assert(serveWebhook(webhooks, "DELETE", "/", ""), Equals, http.StatusMethodNotAllowed)
+}
+
+func mustFetchAnyEvent(c *C) *Event {
+	card := mustCreateCardFixture(sharedClient, "VisaSuccess")
+	_, _, err := sharedClient.Card.Charge(card.Id, &Debit{Amount: 100})
+	c.Assert(err, IsNil)
+
+	eventPage, _, err := sharedClient.Event.List()
+	c.Assert(err, IsNil)
+	if len(eventPage.Events) == 0 {
+		c.Skip("No event has been created yet")
+	}
+	return &eventPage.Events[0]
+}
+
+func (s *WebhookSuite) TestVerification(c *C) {
+	event := mustFetchAnyEvent(c)
+
+	var handled []string
+	webhooks := NewWebhookHandler(WithEventVerification(sharedClient))
+	webhooks.Handle(event.Type, func(ctx context.Context, event *Event) error {
+		handled = append(handled, event.Id)
+		return nil
+	})
+
+	body, err := json.Marshal(event)
+	c.Assert(err, IsNil)
+	c.Assert(serveWebhook(webhooks, "POST", "/", string(body)), Equals, http.StatusOK)
+
+	forged := *event
+	forged.Type = "debit.succeeded"
+	if event.Type == forged.Type {
+		forged.Type = "debit.failed"
+	}
+	body, err = json.Marshal(&forged)
+	c.Assert(err, IsNil)
+	c.Assert(serveWebhook(webhooks, "POST", "/", string(body)), Equals, http.StatusForbidden)
+
+	c.Assert(serveWebhook(webhooks, "POST", "/", `{"id": "EV123", "type": "debit.succeeded"}`), Equals, http.StatusForbidden)
+	c.Assert(handled, DeepEquals, []string{event.Id})
+}
+
+func (s *WebhookSuite) TestEventStore(c *C) {
+	var attempts int
+	webhooks := NewWebhookHandler(WithEventStore(NewMemoryEventStore(time.Hour)))
+	webhooks.Handle("debit.succeeded", func(ctx context.Context, event *Event) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	body := `{"id": "EV1", "type": "debit.succeeded"}`
+	c.Assert(serveWebhook(webhooks, "POST", "/", body), Equals, http.StatusInternalServerError)
+	c.Assert(serveWebhook(webhooks, "POST", "/", body), Equals, http.StatusOK)
+	c.Assert(serveWebhook(webhooks, "POST", "/", body), Equals, http.StatusOK)
+	c.Assert(attempts, Equals, 2)
+}
+
+type EventStoreSuite struct{}
+
+var _ = Suite(&EventStoreSuite{})
+
+func (s *EventStoreSuite) TestMemoryEventStore(c *C) {
+	ctx := context.Background()
+	store := NewMemoryEventStore(time.Hour)
+
+	claimed, err := store.Claim(ctx, "EV1")
+	c.Assert(err, IsNil)
+	c.Assert(claimed, Equals, true)
+	claimed, _ = store.Claim(ctx, "EV1")
+	c.Assert(claimed, Equals, false)
+
+	c.Assert(store.Release(ctx, "EV1"), IsNil)
+	claimed, _ = store.Claim(ctx, "EV1")
+	c.Assert(claimed, Equals, true)
+}
+
+func (s *EventStoreSuite) TestExpiry(c *C) {
+	ctx := context.Background()
+	store := NewMemoryEventStore(time.Millisecond)
+
+	claimed, _ := store.Claim(ctx, "EV1")
+	c.Assert(claimed, Equals, true)
+	time.Sleep(2 * time.Millisecond)
+	claimed, _ = store.Claim(ctx, "EV1")
+	c.Assert(claimed, Equals, true)
+}
+
+func (s *EventStoreSuite) TestFileEventStore(c *C) {
+	ctx := context.Background()
+	path := c.MkDir() + "/events.json"
+
+	store, err := NewFileEventStore(path, time.Hour)
+	c.Assert(err, IsNil)
+	claimed, err := store.Claim(ctx, "EV1")
+	c.Assert(err, IsNil)
+	c.Assert(claimed, Equals, true)
+	claimed, _ = store.Claim(ctx, "EV2")
+	c.Assert(claimed, Equals, true)
+	c.Assert(store.Release(ctx, "EV2"), IsNil)
+
+	// The claims survive reopening the store.
+	store, err = NewFileEventStore(path, time.Hour)
+	c.Assert(err, IsNil)
+	claimed, _ = store.Claim(ctx, "EV1")
+	c.Assert(claimed, Equals, false)
+	claimed, _ = store.Claim(ctx, "EV2")
+	c.Assert(claimed, Equals, true)
+}
+
+func (s *EventStoreSuite) TestFileEventStoreAfterCrash(c *C) {
+	ctx := context.Background()
+	path := c.MkDir() + "/events.json"
+	expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339Nano)
+	// The last claim was cut short while it was appended.
+	data := `{"id":"EV1","expires_at":"` + expiresAt + `"}` + "\n" + `{"id":"EV2","exp`
+	c.Assert(ioutil.WriteFile(path, []byte(data), 0600), IsNil)
+
+	store, err := NewFileEventStore(path, time.Hour)
+	c.Assert(err, IsNil)
+	claimed, _ := store.Claim(ctx, "EV1")
+	c.Assert(claimed, Equals, false)
+	claimed, _ = store.Claim(ctx, "EV2")
+	c.Assert(claimed, Equals, true)
+
+	store, err = NewFileEventStore(path, time.Hour)
+	c.Assert(err, IsNil)
+	claimed, _ = store.Claim(ctx, "EV2")
+	c.Assert(claimed, Equals, false)
+}
+
+type EventTailerSuite struct{}
+
+var _ = Suite(&EventTailerSuite{})
+
+func (s *EventTailerSuite) TestCursor(c *C) {
+	at := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	later := at.Add(time.Second)
+	cursor := &EventCursor{}
+	cursor.advance(&Event{Id: "EV1", OccurredAt: &at})
+	cursor.advance(&Event{Id: "EV2", OccurredAt: &at})
+
+	c.Assert(cursor.passed(&Event{Id: "EV1", OccurredAt: &at}), Equals, true)
+	c.Assert(cursor.passed(&Event{Id: "EV3", OccurredAt: &at}), Equals, false)
+	c.Assert(cursor.passed(&Event{Id: "EV4", OccurredAt: &later}), Equals, false)
+
+	cursor.advance(&Event{Id: "EV4", OccurredAt: &later})
+	c.Assert(cursor.Ids, DeepEquals, []string{"EV4"})
+	c.Assert(cursor.passed(&Event{Id: "EV3", OccurredAt: &at}), Equals, true)
+}
+
+func (s *EventTailerSuite) TestFileCursorStore(c *C) {
+	ctx := context.Background()
+	store := NewFileCursorStore(c.MkDir() + "/events.cursor")
+
+	cursor, err := store.Load(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(cursor, IsNil)
+
+	at := time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC)
+	c.Assert(store.Save(ctx, &EventCursor{OccurredAt: at, Ids: []string{"EV1"}}), IsNil)
+	cursor, err = store.Load(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(cursor.OccurredAt.Equal(at), Equals, true)
+	c.Assert(cursor.Ids, DeepEquals, []string{"EV1"})
+}
+
+func (s *EventTailerSuite) TestRun(c *C) {
+	event := mustFetchAnyEvent(c)
+	store := &MemoryCursorStore{}
+	tailer := NewEventTailer(sharedClient, WithCursorStore(store), WithTailStart(*event.OccurredAt))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, errc := tailer.Events(ctx)
+	first := <-events
+	c.Assert(first, NotNil)
+	c.Assert(first.OccurredAt.Before(*event.OccurredAt), Equals, false)
+	cancel()
+	for range events {
+	}
+	c.Assert(errors.Is(<-errc, context.Canceled), Equals, true)
+
+	// A restarted tailer resumes past the events emitted.
+	cursor, err := store.Load(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(cursor.passed(first), Equals, true)
+}
+
+func (s *EventTailerSuite) TestRunResumesWithoutDuplicates(c *C) {
+	pages := map[string]string{
+		"": `{"events": [
+			{"id": "EV1", "type": "debit.created", "occurred_at": "2015-01-02T03:04:05Z"},
+			{"id": "EV2", "type": "debit.created", "occurred_at": "2015-01-02T03:04:06Z"}
+		], "meta": {"next": "/events?offset=2"}}`,
+		"2": `{"events": [
+			{"id": "EV3", "type": "debit.succeeded", "occurred_at": "2015-01-02T03:04:06Z"}
+		], "meta": {}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every poll lists every event; the tailer skips those it emitted.
+		w.Write([]byte(pages[r.URL.Query().Get("offset")]))
+	}))
+	defer server.Close()
+	client := NewClient(nil, "not-a-secret", WithBaseUrl(server.URL))
+	store := &MemoryCursorStore{}
+
+	var emitted []string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, errc := NewEventTailer(client, WithCursorStore(store), WithPollInterval(time.Millisecond, time.Millisecond)).Events(ctx)
+	for len(emitted) < 2 {
+		emitted = append(emitted, (<-events).Id)
+	}
+	cancel()
+	for event := range events {
+		emitted = append(emitted, event.Id)
+	}
+	c.Assert(errors.Is(<-errc, context.Canceled), Equals, true)
+
+	// The restarted tailer emits the remaining events only, however many
+	// times it polls.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	events, errc = NewEventTailer(client, WithCursorStore(store), WithPollInterval(time.Millisecond, time.Millisecond)).Events(ctx)
+	timeout := time.After(50 * time.Millisecond)
+	for done := false; !done; {
+		select {
+		case event := <-events:
+			emitted = append(emitted, event.Id)
+		case <-timeout:
+			done = true
+		}
+	}
+	cancel()
+	for event := range events {
+		emitted = append(emitted, event.Id)
+	}
+	c.Assert(errors.Is(<-errc, context.Canceled), Equals, true)
+	c.Assert(emitted, DeepEquals, []string{"EV1", "EV2", "EV3"})
+}
+
+func (s *EventTailerSuite) TestPollInterval(c *C) {
+	tailer := NewEventTailer(sharedClient, WithPollInterval(0, -time.Second))
+	c.Assert(tailer.minInterval, Equals, defaultMinPollInterval)
+	c.Assert(tailer.maxInterval, Equals, defaultMaxPollInterval)
+
+	tailer = NewEventTailer(sharedClient, WithPollInterval(time.Minute, time.Second))
+	c.Assert(tailer.minInterval, Equals, time.Minute)
+	c.Assert(tailer.maxInterval, Equals, time.Minute)
+
+	tailer = NewEventTailer(sharedClient, WithPollInterval(10*time.Minute, 0))
+	c.Assert(tailer.minInterval, Equals, 10*time.Minute)
+	c.Assert(tailer.maxInterval, Equals, 10*time.Minute)
+}
@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -34,7 +35,7 @@ type BankAccount struct {
 
 type BankAccountLinks struct {
 	BankAccountVerification string `json:"bank_account_verification"`
-	Customer                string `json:customer`
+	Customer                string `json:"customer"`
 }
 
 // BankAccountPage holds a paginated set of bank accounts
@@ -43,23 +44,29 @@ type BankAccountPage struct {
 	*PaginationParams
 }
 
+type BankAccountIterator = Iterator[BankAccount]
+
 type bankAccountResponse struct {
 	BankAccounts []BankAccount             `json:"bank_accounts"`
-	Meta         map[string]interface{}    `json:"meta"`
+	Meta         *PaginationParams         `json:"meta"`
 	Links        *bankAccountResponseLinks `json:"links"`
 }
 
 type bankAccountResponseLinks struct {
-	Verification  string `json:"bank_accounts.bank_account_verification"`
-	Verifications string `json:"bank_accounts.bank_account_verifications"`
-	Credits       string `json:"bank_accounts.credits"`
-	Customer      string `json:"bank_accounts.customer"`
-	Debits        string `json:"bank_accounts.debits"`
+	Verification  string `json:"bank_accounts.bank_account_verification" template:"/verifications/{bank_accounts.bank_account_verification}"`
+	Verifications string `json:"bank_accounts.bank_account_verifications" template:"/bank_accounts/{bank_accounts.self}/verifications"`
+	Credits       string `json:"bank_accounts.credits" template:"/bank_accounts/{bank_accounts.self}/credits"`
+	Customer      string `json:"bank_accounts.customer" template:"/customers/{bank_accounts.customer}"`
+	Debits        string `json:"bank_accounts.debits" template:"/bank_accounts/{bank_accounts.self}/debits"`
 }
 
 func (s *BankAccountService) Create(account *BankAccount) (*BankAccount, *http.Response, error) {
+	return s.CreateContext(context.Background(), account)
+}
+
+func (s *BankAccountService) CreateContext(ctx context.Context, account *BankAccount) (*BankAccount, *http.Response, error) {
 	accountResponse := new(bankAccountResponse)
-	httpResponse, err := s.client.POST("/bank_accounts", nil, account, accountResponse)
+	httpResponse, err := s.client.POSTContext(ctx, "/bank_accounts", nil, account, accountResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -67,8 +74,12 @@ func (s *BankAccountService) Create(account *BankAccount) (*BankAccount, *http.R
 }
 
 func (s *BankAccountService) Delete(accountId string) (bool, *http.Response, error) {
+	return s.DeleteContext(context.Background(), accountId)
+}
+
+func (s *BankAccountService) DeleteContext(ctx context.Context, accountId string) (bool, *http.Response, error) {
 	path := fmt.Sprintf("/bank_accounts/%v", accountId)
-	httpResponse, err := s.client.DELETE(path, nil, nil, nil)
+	httpResponse, err := s.client.DELETEContext(ctx, path, nil, nil, nil)
 	if err != nil {
 		return false, httpResponse, err
 	}
@@ -78,9 +89,13 @@ func (s *BankAccountService) Delete(accountId string) (bool, *http.Response, err
 }
 
 func (s *BankAccountService) Fetch(accountId string) (*BankAccount, *http.Response, error) {
+	return s.FetchContext(context.Background(), accountId)
+}
+
+func (s *BankAccountService) FetchContext(ctx context.Context, accountId string) (*BankAccount, *http.Response, error) {
 	path := fmt.Sprintf("/bank_accounts/%v", accountId)
 	accountResponse := new(bankAccountResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, accountResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, accountResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -88,9 +103,13 @@ func (s *BankAccountService) Fetch(accountId string) (*BankAccount, *http.Respon
 }
 
 func (s *BankAccountService) Update(accountId string, params map[string]interface{}) (*BankAccount, *http.Response, error) {
+	return s.UpdateContext(context.Background(), accountId, params)
+}
+
+func (s *BankAccountService) UpdateContext(ctx context.Context, accountId string, params map[string]interface{}) (*BankAccount, *http.Response, error) {
 	path := fmt.Sprintf("/bank_accounts/%v", accountId)
 	accountResponse := new(bankAccountResponse)
-	httpResponse, err := s.client.PUT(path, nil, params, accountResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, accountResponse)
 
 	if err != nil {
 		return nil, httpResponse, err
@@ -99,33 +118,104 @@ func (s *BankAccountService) Update(accountId string, params map[string]interfac
 }
 
 func (s *BankAccountService) UpdateMeta(accountId string, meta map[string]interface{}) (*BankAccount, *http.Response, error) {
-	return s.Update(accountId, map[string]interface{}{"meta": meta})
+	return s.UpdateMetaContext(context.Background(), accountId, meta)
+}
+
+func (s *BankAccountService) UpdateMetaContext(ctx context.Context, accountId string, meta map[string]interface{}) (*BankAccount, *http.Response, error) {
+	return s.UpdateContext(ctx, accountId, map[string]interface{}{"meta": meta})
 }
 
 func (s *BankAccountService) List(args ...interface{}) (*BankAccountPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *BankAccountService) ListContext(ctx context.Context, args ...interface{}) (*BankAccountPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/bank_accounts", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *BankAccountService) ListWith(opts *ListOptions) (*BankAccountPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *BankAccountService) ListWithContext(ctx context.Context, opts *ListOptions) (*BankAccountPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *BankAccountService) list(ctx context.Context, path string, query map[string]interface{}) (*BankAccountPage, *http.Response, error) {
 	accountResponse := new(bankAccountResponse)
-	httpResponse, err := s.client.GET("/bank_accounts", query, nil, accountResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, accountResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &BankAccountPage{
 		BankAccounts:     accountResponse.BankAccounts,
-		PaginationParams: NewPaginationParams(accountResponse.Meta),
+		PaginationParams: accountResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of bank accounts at href, such as the Next or Previous
+// link of another page.
+func (s *BankAccountService) FetchPage(href string) (*BankAccountPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *BankAccountService) FetchPageContext(ctx context.Context, href string) (*BankAccountPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all bank accounts, fetching pages as needed. args are
+// the same as for List.
+func (s *BankAccountService) Iter(args ...interface{}) *BankAccountIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *BankAccountService) IterContext(ctx context.Context, args ...interface{}) *BankAccountIterator {
+	return newIterator(ctx, "/bank_accounts", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *BankAccountService) IterWith(opts *ListOptions) *BankAccountIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *BankAccountService) IterWithContext(ctx context.Context, opts *ListOptions) *BankAccountIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *BankAccountService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]BankAccount, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.BankAccounts, page.PaginationParams, nil
+}
+
 func (s *BankAccountService) AssociateWithCustomer(accountId string, customerId string) (*BankAccount, *http.Response, error) {
-	return s.Update(accountId, map[string]interface{}{
+	return s.AssociateWithCustomerContext(context.Background(), accountId, customerId)
+}
+
+func (s *BankAccountService) AssociateWithCustomerContext(ctx context.Context, accountId string, customerId string) (*BankAccount, *http.Response, error) {
+	return s.UpdateContext(ctx, accountId, map[string]interface{}{
 		"customer": fmt.Sprintf("/customers/%v", customerId),
 	})
 }
 
 func (s *BankAccountService) Debit(accountId string, debit *Debit) (*Debit, *http.Response, error) {
+	return s.DebitContext(context.Background(), accountId, debit)
+}
+
+func (s *BankAccountService) DebitContext(ctx context.Context, accountId string, debit *Debit) (*Debit, *http.Response, error) {
 	path := fmt.Sprintf("/bank_accounts/%v/debits", accountId)
 	debitResponse := new(debitResponse)
-	httpResponse, err := s.client.POST(path, nil, &DebitRequest{
+	httpResponse, err := s.client.idempotentPOSTContext(ctx, path, nil, &DebitRequest{
 		Debits: []Debit{*debit},
 	}, debitResponse)
 	if err != nil {
@@ -135,9 +225,13 @@ func (s *BankAccountService) Debit(accountId string, debit *Debit) (*Debit, *htt
 }
 
 func (s *BankAccountService) Credit(bankAccountId string, credit *Credit) (*Credit, *http.Response, error) {
+	return s.CreditContext(context.Background(), bankAccountId, credit)
+}
+
+func (s *BankAccountService) CreditContext(ctx context.Context, bankAccountId string, credit *Credit) (*Credit, *http.Response, error) {
 	path := fmt.Sprintf("/bank_accounts/%v/credits", bankAccountId)
 	creditResponse := new(creditResponse)
-	httpResponse, err := s.client.POST(path, nil, credit, creditResponse)
+	httpResponse, err := s.client.idempotentPOSTContext(ctx, path, nil, credit, creditResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
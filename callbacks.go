@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -29,21 +30,27 @@ type CallbackPage struct {
 	*PaginationParams
 }
 
+type CallbackIterator = Iterator[Callback]
+
 type callbackResponse struct {
 	Callbacks []Callback             `json:"callbacks"`
-	Meta      map[string]interface{} `json:"meta"`
+	Meta      *PaginationParams      `json:"meta"`
 	Links     *callbackResponseLinks `json:"links"`
 }
 
 type callbackResponseLinks struct{}
 
 func (s *CallbackService) Create(url, method string) (*Callback, *http.Response, error) {
+	return s.CreateContext(context.Background(), url, method)
+}
+
+func (s *CallbackService) CreateContext(ctx context.Context, url, method string) (*Callback, *http.Response, error) {
 	callbackResponse := new(callbackResponse)
 	callback := &Callback{
 		Url:    url,
 		Method: method,
 	}
-	httpResponse, err := s.client.POST("/callbacks", nil, callback, callbackResponse)
+	httpResponse, err := s.client.POSTContext(ctx, "/callbacks", nil, callback, callbackResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -51,9 +58,13 @@ func (s *CallbackService) Create(url, method string) (*Callback, *http.Response,
 }
 
 func (s *CallbackService) Fetch(callbackId string) (*Callback, *http.Response, error) {
+	return s.FetchContext(context.Background(), callbackId)
+}
+
+func (s *CallbackService) FetchContext(ctx context.Context, callbackId string) (*Callback, *http.Response, error) {
 	path := fmt.Sprintf("/callbacks/%v", callbackId)
 	callbackResponse := new(callbackResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, callbackResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, callbackResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -61,8 +72,12 @@ func (s *CallbackService) Fetch(callbackId string) (*Callback, *http.Response, e
 }
 
 func (s *CallbackService) Delete(callbackId string) (bool, *http.Response, error) {
+	return s.DeleteContext(context.Background(), callbackId)
+}
+
+func (s *CallbackService) DeleteContext(ctx context.Context, callbackId string) (bool, *http.Response, error) {
 	path := fmt.Sprintf("/callbacks/%v", callbackId)
-	httpResponse, err := s.client.DELETE(path, nil, nil, nil)
+	httpResponse, err := s.client.DELETEContext(ctx, path, nil, nil, nil)
 	if err != nil {
 		return false, httpResponse, err
 	}
@@ -72,15 +87,74 @@ func (s *CallbackService) Delete(callbackId string) (bool, *http.Response, error
 }
 
 func (s *CallbackService) List(args ...interface{}) (*CallbackPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *CallbackService) ListContext(ctx context.Context, args ...interface{}) (*CallbackPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/callbacks", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *CallbackService) ListWith(opts *ListOptions) (*CallbackPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *CallbackService) ListWithContext(ctx context.Context, opts *ListOptions) (*CallbackPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *CallbackService) list(ctx context.Context, path string, query map[string]interface{}) (*CallbackPage, *http.Response, error) {
 	callbackResponse := new(callbackResponse)
-	httpResponse, err := s.client.GET("/callbacks", query, nil, callbackResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, callbackResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &CallbackPage{
 		Callbacks:        callbackResponse.Callbacks,
-		PaginationParams: NewPaginationParams(callbackResponse.Meta),
+		PaginationParams: callbackResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
+
+// FetchPage fetches the page of callbacks at href, such as the Next or Previous
+// link of another page.
+func (s *CallbackService) FetchPage(href string) (*CallbackPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *CallbackService) FetchPageContext(ctx context.Context, href string) (*CallbackPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all callbacks, fetching pages as needed. args are
+// the same as for List.
+func (s *CallbackService) Iter(args ...interface{}) *CallbackIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *CallbackService) IterContext(ctx context.Context, args ...interface{}) *CallbackIterator {
+	return newIterator(ctx, "/callbacks", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *CallbackService) IterWith(opts *ListOptions) *CallbackIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *CallbackService) IterWithContext(ctx context.Context, opts *ListOptions) *CallbackIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *CallbackService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Callback, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Callbacks, page.PaginationParams, nil
+}
@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -43,23 +44,29 @@ type CardHoldPage struct {
 	*PaginationParams
 }
 
+type CardHoldIterator = Iterator[CardHold]
+
 type cardHoldResponse struct {
 	CardHolds []CardHold             `json:"card_holds"`
 	Links     *cardHoldResponseLinks `json:"links"`
-	Meta      map[string]interface{} `json:"meta,omitempty"`
+	Meta      *PaginationParams      `json:"meta,omitempty"`
 }
 
 type cardHoldResponseLinks struct {
-	Card   string `json:"card_holds.card"`
-	Debit  string `json:"card_holds.debit"`
-	Debits string `json:"card_holds.debits"`
-	Events string `json:"card_holds.events"`
+	Card   string `json:"card_holds.card" template:"/cards/{card_holds.card}"`
+	Debit  string `json:"card_holds.debit" template:"/debits/{card_holds.debit}"`
+	Debits string `json:"card_holds.debits" template:"/card_holds/{card_holds.self}/debits"`
+	Events string `json:"card_holds.events" template:"/card_holds/{card_holds.self}/events"`
 }
 
 func (s *CardHoldService) Create(cardId string, hold *CardHold) (*CardHold, *http.Response, error) {
+	return s.CreateContext(context.Background(), cardId, hold)
+}
+
+func (s *CardHoldService) CreateContext(ctx context.Context, cardId string, hold *CardHold) (*CardHold, *http.Response, error) {
 	path := fmt.Sprintf("/cards/%v/card_holds", cardId)
 	holdResponse := new(cardHoldResponse)
-	httpResponse, err := s.client.POST(path, nil, hold, holdResponse)
+	httpResponse, err := s.client.POSTContext(ctx, path, nil, hold, holdResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -67,9 +74,13 @@ func (s *CardHoldService) Create(cardId string, hold *CardHold) (*CardHold, *htt
 }
 
 func (s *CardHoldService) Fetch(holdId string) (*CardHold, *http.Response, error) {
+	return s.FetchContext(context.Background(), holdId)
+}
+
+func (s *CardHoldService) FetchContext(ctx context.Context, holdId string) (*CardHold, *http.Response, error) {
 	path := fmt.Sprintf("/card_holds/%v", holdId)
 	holdResponse := new(cardHoldResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, holdResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, holdResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -78,35 +89,118 @@ func (s *CardHoldService) Fetch(holdId string) (*CardHold, *http.Response, error
 
 // The holds are returned sorted from recent to oldest
 func (s *CardHoldService) List(args ...interface{}) (*CardHoldPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *CardHoldService) ListContext(ctx context.Context, args ...interface{}) (*CardHoldPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/card_holds", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *CardHoldService) ListWith(opts *ListOptions) (*CardHoldPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *CardHoldService) ListWithContext(ctx context.Context, opts *ListOptions) (*CardHoldPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *CardHoldService) list(ctx context.Context, path string, query map[string]interface{}) (*CardHoldPage, *http.Response, error) {
 	holdResponse := new(cardHoldResponse)
-	httpResponse, err := s.client.GET("/card_holds", query, nil, holdResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, holdResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &CardHoldPage{
 		CardHolds:        holdResponse.CardHolds,
-		PaginationParams: NewPaginationParams(holdResponse.Meta),
+		PaginationParams: holdResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of card holds at href, such as the Next or Previous
+// link of another page.
+func (s *CardHoldService) FetchPage(href string) (*CardHoldPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *CardHoldService) FetchPageContext(ctx context.Context, href string) (*CardHoldPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all card holds, fetching pages as needed. args are
+// the same as for List.
+func (s *CardHoldService) Iter(args ...interface{}) *CardHoldIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *CardHoldService) IterContext(ctx context.Context, args ...interface{}) *CardHoldIterator {
+	return newIterator(ctx, "/card_holds", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *CardHoldService) IterWith(opts *ListOptions) *CardHoldIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *CardHoldService) IterWithContext(ctx context.Context, opts *ListOptions) *CardHoldIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *CardHoldService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]CardHold, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.CardHolds, page.PaginationParams, nil
+}
+
 func (s *CardHoldService) Update(holdId string, params map[string]interface{}) (*CardHold, *http.Response, error) {
+	return s.UpdateContext(context.Background(), holdId, params)
+}
+
+func (s *CardHoldService) UpdateContext(ctx context.Context, holdId string, params map[string]interface{}) (*CardHold, *http.Response, error) {
 	path := fmt.Sprintf("/card_holds/%v", holdId)
 	holdResponse := new(cardHoldResponse)
-	httpResponse, err := s.client.PUT(path, nil, params, holdResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, holdResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &holdResponse.CardHolds[0], httpResponse, nil
 }
 
+// UpdateWith updates the fields of a card hold set in update, once
+// update.Validate accepts them.
+func (s *CardHoldService) UpdateWith(holdId string, update *CardHoldUpdate) (*CardHold, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), holdId, update)
+}
+
+func (s *CardHoldService) UpdateWithContext(ctx context.Context, holdId string, update *CardHoldUpdate) (*CardHold, *http.Response, error) {
+	path := fmt.Sprintf("/card_holds/%v", holdId)
+	cardHoldResponse := new(cardHoldResponse)
+	httpResponse, err := s.client.putUpdate(ctx, path, update, cardHoldResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &cardHoldResponse.CardHolds[0], httpResponse, nil
+}
+
 // Captures a previously created card hold. This creates a Debit. Any amount up
 // to the hold amount may be captured.
 func (s *CardHoldService) Capture(holdId string, debit *Debit) (*Debit, *http.Response, error) {
+	return s.CaptureContext(context.Background(), holdId, debit)
+}
+
+func (s *CardHoldService) CaptureContext(ctx context.Context, holdId string, debit *Debit) (*Debit, *http.Response, error) {
 	path := fmt.Sprintf("/card_holds/%v/debits", holdId)
 	debitResponse := new(debitResponse)
-	httpResponse, err := s.client.POST(path, nil, debit, debitResponse)
+	httpResponse, err := s.client.idempotentPOSTContext(ctx, path, nil, debit, debitResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -115,12 +209,16 @@ func (s *CardHoldService) Capture(holdId string, debit *Debit) (*Debit, *http.Re
 
 // Cancels the hold. Once voided, the hold can no longer be captured.
 func (s *CardHoldService) Void(holdId string) (*CardHold, *http.Response, error) {
+	return s.VoidContext(context.Background(), holdId)
+}
+
+func (s *CardHoldService) VoidContext(ctx context.Context, holdId string) (*CardHold, *http.Response, error) {
 	path := fmt.Sprintf("/card_holds/%v", holdId)
 	reqBody := map[string]interface{}{
 		"is_void": true,
 	}
 	holdResponse := new(cardHoldResponse)
-	httpResponse, err := s.client.PUT(path, nil, reqBody, holdResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, reqBody, holdResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
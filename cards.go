@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -38,16 +39,32 @@ type CardLinks struct {
 	Customer string `json:"customer"`
 }
 
+// CardUpdate holds the fields of a card to update with
+// CardService.UpdateWith. Unset fields are left as they are.
+type CardUpdate struct {
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// Validate checks that the meta keys of the update are not blank.
+func (u *CardUpdate) Validate() error {
+	if u == nil {
+		return errNoUpdate
+	}
+	problems := updateProblems{}
+	checkMeta(problems, u.Meta)
+	return problems.err()
+}
+
 type cardResponse struct {
-	Cards []Card                 `json:"cards"`
-	Links *cardResponseLinks     `json:"links"`
-	Meta  map[string]interface{} `json:"meta"`
+	Cards []Card             `json:"cards"`
+	Links *cardResponseLinks `json:"links"`
+	Meta  *PaginationParams  `json:"meta"`
 }
 
 type cardResponseLinks struct {
-	CardHolds string `json:"cards.card_holds"`
-	Customers string `json:"cards.customers"`
-	Debits    string `json:"cards.debits"`
+	CardHolds string `json:"cards.card_holds" template:"/cards/{cards.self}/card_holds"`
+	Customers string `json:"cards.customers" template:"/customers/{cards.customer}"`
+	Debits    string `json:"cards.debits" template:"/cards/{cards.self}/debits"`
 }
 
 type CardPage struct {
@@ -55,6 +72,8 @@ type CardPage struct {
 	*PaginationParams
 }
 
+type CardIterator = Iterator[Card]
+
 // Creates a card on the server. This method is not recommended in production
 // environments. Instead, use balanced.js to create cards.
 //
@@ -65,8 +84,12 @@ type CardPage struct {
 // PostalCode
 // CountryCode (Country code, ISO 3166-1 alpha-3)
 func (s *CardService) Create(card *Card) (*Card, *http.Response, error) {
+	return s.CreateContext(context.Background(), card)
+}
+
+func (s *CardService) CreateContext(ctx context.Context, card *Card) (*Card, *http.Response, error) {
 	cardResponse := new(cardResponse)
-	httpResponse, err := s.client.POST("/cards", nil, card, cardResponse)
+	httpResponse, err := s.client.POSTContext(ctx, "/cards", nil, card, cardResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -74,8 +97,12 @@ func (s *CardService) Create(card *Card) (*Card, *http.Response, error) {
 }
 
 func (s *CardService) Delete(cardId string) (bool, *http.Response, error) {
+	return s.DeleteContext(context.Background(), cardId)
+}
+
+func (s *CardService) DeleteContext(ctx context.Context, cardId string) (bool, *http.Response, error) {
 	path := fmt.Sprintf("/cards/%v", cardId)
-	httpResponse, err := s.client.DELETE(path, nil, nil, nil)
+	httpResponse, err := s.client.DELETEContext(ctx, path, nil, nil, nil)
 	if err != nil {
 		return false, httpResponse, err
 	}
@@ -85,9 +112,13 @@ func (s *CardService) Delete(cardId string) (bool, *http.Response, error) {
 }
 
 func (s *CardService) Fetch(cardId string) (*Card, *http.Response, error) {
+	return s.FetchContext(context.Background(), cardId)
+}
+
+func (s *CardService) FetchContext(ctx context.Context, cardId string) (*Card, *http.Response, error) {
 	path := fmt.Sprintf("/cards/%v", cardId)
 	cardResponse := new(cardResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, cardResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, cardResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -95,22 +126,102 @@ func (s *CardService) Fetch(cardId string) (*Card, *http.Response, error) {
 }
 
 func (s *CardService) List(args ...interface{}) (*CardPage, *http.Response, error) {
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *CardService) ListContext(ctx context.Context, args ...interface{}) (*CardPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/cards", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *CardService) ListWith(opts *ListOptions) (*CardPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *CardService) ListWithContext(ctx context.Context, opts *ListOptions) (*CardPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *CardService) list(ctx context.Context, path string, query map[string]interface{}) (*CardPage, *http.Response, error) {
 	cardResponse := new(cardResponse)
-	httpResponse, err := s.client.GET("/cards", query, nil, cardResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, cardResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &CardPage{
 		Cards:            cardResponse.Cards,
-		PaginationParams: NewPaginationParams(cardResponse.Meta),
+		PaginationParams: cardResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of cards at href, such as the Next or Previous
+// link of another page.
+func (s *CardService) FetchPage(href string) (*CardPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *CardService) FetchPageContext(ctx context.Context, href string) (*CardPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all cards, fetching pages as needed. args are
+// the same as for List.
+func (s *CardService) Iter(args ...interface{}) *CardIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *CardService) IterContext(ctx context.Context, args ...interface{}) *CardIterator {
+	return newIterator(ctx, "/cards", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *CardService) IterWith(opts *ListOptions) *CardIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *CardService) IterWithContext(ctx context.Context, opts *ListOptions) *CardIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *CardService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Card, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Cards, page.PaginationParams, nil
+}
+
 func (s *CardService) Update(cardId string, params map[string]interface{}) (*Card, *http.Response, error) {
+	return s.UpdateContext(context.Background(), cardId, params)
+}
+
+func (s *CardService) UpdateContext(ctx context.Context, cardId string, params map[string]interface{}) (*Card, *http.Response, error) {
 	path := fmt.Sprintf("/cards/%v", cardId)
 	cardResponse := new(cardResponse)
-	httpResponse, err := s.client.PUT(path, nil, params, cardResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, cardResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &cardResponse.Cards[0], httpResponse, nil
+}
+
+// UpdateWith updates the fields of a card set in update, once
+// update.Validate accepts them.
+func (s *CardService) UpdateWith(cardId string, update *CardUpdate) (*Card, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), cardId, update)
+}
+
+func (s *CardService) UpdateWithContext(ctx context.Context, cardId string, update *CardUpdate) (*Card, *http.Response, error) {
+	path := fmt.Sprintf("/cards/%v", cardId)
+	cardResponse := new(cardResponse)
+	httpResponse, err := s.client.putUpdate(ctx, path, update, cardResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -118,28 +229,43 @@ func (s *CardService) Update(cardId string, params map[string]interface{}) (*Car
 }
 
 func (s *CardService) AssociateWithCustomer(cardId, customerId string) (*Card, *http.Response, error) {
-	return s.Update(cardId, map[string]interface{}{
+	return s.AssociateWithCustomerContext(context.Background(), cardId, customerId)
+}
+
+func (s *CardService) AssociateWithCustomerContext(ctx context.Context, cardId, customerId string) (*Card, *http.Response, error) {
+	return s.UpdateContext(ctx, cardId, map[string]interface{}{
 		"customer": fmt.Sprintf("/customers/%v", customerId),
 	})
 }
 
 func (s *CardService) Charge(cardId string, debit *Debit) (*Debit, *http.Response, error) {
+	return s.ChargeContext(context.Background(), cardId, debit)
+}
+
+func (s *CardService) ChargeContext(ctx context.Context, cardId string, debit *Debit) (*Debit, *http.Response, error) {
 	path := fmt.Sprintf("/cards/%v/debits", cardId)
 	debitResponse := new(debitResponse)
-	httpResponse, err := s.client.POST(path, nil, debit, debitResponse)
+	httpResponse, err := s.client.idempotentPOSTContext(ctx, path, nil, debit, debitResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &debitResponse.Debits[0], httpResponse, nil
 }
 
+// maxCardCredit is the most that can be credited to a card at once, in cents.
+const maxCardCredit = 250000
+
 func (s *CardService) Credit(cardId string, credit *Credit) (*Credit, *http.Response, error) {
-	if credit.Amount > 250000 {
-		return nil, nil, fmt.Errorf("Cannot credit more than $2,500 to a card, but tried crediting %.2f", float32(credit.Amount)/100)
+	return s.CreditContext(context.Background(), cardId, credit)
+}
+
+func (s *CardService) CreditContext(ctx context.Context, cardId string, credit *Credit) (*Credit, *http.Response, error) {
+	if credit.Amount > maxCardCredit {
+		return nil, nil, fmt.Errorf("Cannot credit more than %v to a card, but tried crediting %v", USD(maxCardCredit), credit.Money())
 	}
 	path := fmt.Sprintf("/cards/%v/credits", cardId)
 	creditResponse := new(creditResponse)
-	httpResponse, err := s.client.POST(path, nil, credit, creditResponse)
+	httpResponse, err := s.client.idempotentPOSTContext(ctx, path, nil, credit, creditResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -30,9 +31,9 @@ type Credit struct {
 }
 
 type creditResponse struct {
-	Credits []Credit               `json:"credits"`
-	Links   *creditResponseLinks   `json:"links"`
-	Meta    map[string]interface{} `json:"meta"`
+	Credits []Credit             `json:"credits"`
+	Links   *creditResponseLinks `json:"links"`
+	Meta    *PaginationParams    `json:"meta"`
 }
 
 type CreditLinks struct {
@@ -42,11 +43,11 @@ type CreditLinks struct {
 }
 
 type creditResponseLinks struct {
-	Customer    string `json:"credits.customer"`
-	Destination string `json:"credits.destination"`
-	Events      string `json:"credits.events"`
-	Order       string `json:"credits.order"`
-	Reversals   string `json:"credits.reversals"`
+	Customer    string `json:"credits.customer" template:"/customers/{credits.customer}"`
+	Destination string `json:"credits.destination" template:"/resources/{credits.destination}"`
+	Events      string `json:"credits.events" template:"/credits/{credits.self}/events"`
+	Order       string `json:"credits.order" template:"/orders/{credits.order}"`
+	Reversals   string `json:"credits.reversals" template:"/credits/{credits.self}/reversals"`
 }
 
 // CreditPage holds a paginated set of credits
@@ -55,25 +56,43 @@ type CreditPage struct {
 	*PaginationParams
 }
 
+type CreditIterator = Iterator[Credit]
+
 func (s *CreditService) CreateToBankAccount(accountId string, credit *Credit) (*Credit, *http.Response, error) {
-	return s.client.BankAccount.Credit(accountId, credit)
+	return s.CreateToBankAccountContext(context.Background(), accountId, credit)
+}
+
+func (s *CreditService) CreateToBankAccountContext(ctx context.Context, accountId string, credit *Credit) (*Credit, *http.Response, error) {
+	return s.client.BankAccount.CreditContext(ctx, accountId, credit)
 }
 
 func (s *CreditService) CreateToCard(cardId string, credit *Credit) (*Credit, *http.Response, error) {
-	return s.client.Card.Credit(cardId, credit)
+	return s.CreateToCardContext(context.Background(), cardId, credit)
+}
+
+func (s *CreditService) CreateToCardContext(ctx context.Context, cardId string, credit *Credit) (*Credit, *http.Response, error) {
+	return s.client.Card.CreditContext(ctx, cardId, credit)
 }
 
 // CreateForOrder credits money from the order to the seller's BankAccount
 // represented by bankAccountId.
 func (s *CreditService) CreateForOrder(bankAccountId, orderId string, credit *Credit) (*Credit, *http.Response, error) {
+	return s.CreateForOrderContext(context.Background(), bankAccountId, orderId, credit)
+}
+
+func (s *CreditService) CreateForOrderContext(ctx context.Context, bankAccountId, orderId string, credit *Credit) (*Credit, *http.Response, error) {
 	credit.Order = fmt.Sprintf("/orders/%v", orderId)
-	return s.CreateToBankAccount(bankAccountId, credit)
+	return s.CreateToBankAccountContext(ctx, bankAccountId, credit)
 }
 
 func (s *CreditService) Fetch(creditId string) (*Credit, *http.Response, error) {
+	return s.FetchContext(context.Background(), creditId)
+}
+
+func (s *CreditService) FetchContext(ctx context.Context, creditId string) (*Credit, *http.Response, error) {
 	path := fmt.Sprintf("/credits/%v", creditId)
 	creditResponse := new(creditResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, creditResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, creditResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -81,40 +100,158 @@ func (s *CreditService) Fetch(creditId string) (*Credit, *http.Response, error)
 }
 
 func (s *CreditService) List(args ...interface{}) (*CreditPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *CreditService) ListContext(ctx context.Context, args ...interface{}) (*CreditPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/credits", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *CreditService) ListWith(opts *ListOptions) (*CreditPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *CreditService) ListWithContext(ctx context.Context, opts *ListOptions) (*CreditPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *CreditService) list(ctx context.Context, path string, query map[string]interface{}) (*CreditPage, *http.Response, error) {
 	creditResponse := new(creditResponse)
-	httpResponse, err := s.client.GET("/credits", query, nil, creditResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, creditResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &CreditPage{
 		Credits:          creditResponse.Credits,
-		PaginationParams: NewPaginationParams(creditResponse.Meta),
+		PaginationParams: creditResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of credits at href, such as the Next or Previous
+// link of another page.
+func (s *CreditService) FetchPage(href string) (*CreditPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *CreditService) FetchPageContext(ctx context.Context, href string) (*CreditPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all credits, fetching pages as needed. args are
+// the same as for List.
+func (s *CreditService) Iter(args ...interface{}) *CreditIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *CreditService) IterContext(ctx context.Context, args ...interface{}) *CreditIterator {
+	return newIterator(ctx, "/credits", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *CreditService) IterWith(opts *ListOptions) *CreditIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *CreditService) IterWithContext(ctx context.Context, opts *ListOptions) *CreditIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *CreditService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Credit, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Credits, page.PaginationParams, nil
+}
+
 func (s *CreditService) ListForBankAccount(accountId string, args ...interface{}) (*CreditPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListForBankAccountContext(context.Background(), accountId, args...)
+}
+
+func (s *CreditService) ListForBankAccountContext(ctx context.Context, accountId string, args ...interface{}) (*CreditPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/bank_accounts/%v/credits", accountId)
+	return s.list(ctx, path, query)
+}
+
+// ListForBankAccountWith is like ListForBankAccount, but takes typed options, which may be nil.
+func (s *CreditService) ListForBankAccountWith(accountId string, opts *ListOptions) (*CreditPage, *http.Response, error) {
+	return s.ListForBankAccountContext(context.Background(), accountId, opts)
+}
+
+func (s *CreditService) ListForBankAccountWithContext(ctx context.Context, accountId string, opts *ListOptions) (*CreditPage, *http.Response, error) {
+	return s.ListForBankAccountContext(ctx, accountId, opts)
+}
+
+// IterForBankAccount returns an iterator over all credits to a bank account.
+func (s *CreditService) IterForBankAccount(accountId string, args ...interface{}) *CreditIterator {
+	return s.IterForBankAccountContext(context.Background(), accountId, args...)
+}
+
+func (s *CreditService) IterForBankAccountContext(ctx context.Context, accountId string, args ...interface{}) *CreditIterator {
 	path := fmt.Sprintf("/bank_accounts/%v/credits", accountId)
+	return newIterator(ctx, path, args, s.fetchPage)
+}
+
+// IterForBankAccountWith is like IterForBankAccount, but takes typed options, which may be nil.
+func (s *CreditService) IterForBankAccountWith(accountId string, opts *ListOptions) *CreditIterator {
+	return s.IterForBankAccountContext(context.Background(), accountId, opts)
+}
+
+func (s *CreditService) IterForBankAccountWithContext(ctx context.Context, accountId string, opts *ListOptions) *CreditIterator {
+	return s.IterForBankAccountContext(ctx, accountId, opts)
+}
+
+func (s *CreditService) Update(creditId string, params map[string]interface{}) (*Credit, *http.Response, error) {
+	return s.UpdateContext(context.Background(), creditId, params)
+}
+
+func (s *CreditService) UpdateContext(ctx context.Context, creditId string, params map[string]interface{}) (*Credit, *http.Response, error) {
+	path := fmt.Sprintf("/credits/%v", creditId)
 	creditResponse := new(creditResponse)
-	httpResponse, err := s.client.GET(path, query, nil, creditResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, creditResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
-	return &CreditPage{
-		Credits:          creditResponse.Credits,
-		PaginationParams: NewPaginationParams(creditResponse.Meta),
-	}, httpResponse, nil
+	return &creditResponse.Credits[0], httpResponse, nil
 }
 
-func (s *CreditService) Update(creditId string, params map[string]interface{}) (*Credit, *http.Response, error) {
+// UpdateWith updates the fields of a credit set in update, once
+// update.Validate accepts them.
+func (s *CreditService) UpdateWith(creditId string, update *CreditUpdate) (*Credit, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), creditId, update)
+}
+
+func (s *CreditService) UpdateWithContext(ctx context.Context, creditId string, update *CreditUpdate) (*Credit, *http.Response, error) {
 	path := fmt.Sprintf("/credits/%v", creditId)
 	creditResponse := new(creditResponse)
-	httpResponse, err := s.client.PUT(path, nil, params, creditResponse)
+	httpResponse, err := s.client.putUpdate(ctx, path, update, creditResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &creditResponse.Credits[0], httpResponse, nil
 }
+
+// FetchDestination fetches the card or bank account the credit paid money to.
+func (s *CreditService) FetchDestination(credit *Credit) (FundingInstrument, *http.Response, error) {
+	return s.FetchDestinationContext(context.Background(), credit)
+}
+
+func (s *CreditService) FetchDestinationContext(ctx context.Context, credit *Credit) (FundingInstrument, *http.Response, error) {
+	href, err := LinkHref("credits.destination", credit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.client.FundingInstrument.FetchContext(ctx, href)
+}
@@ -1,8 +1,10 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -17,7 +19,7 @@ type Customer struct {
 	DobMonth     int    `json:"dob_month,omitempty"`
 	DobYear      int    `json:"dob_year,omitempty"`
 	Ein          string `json:"ein,omitempty"`
-	Name         string `json:"ein,omitempty"`
+	Name         string `json:"name,omitempty"`
 	Phone        string `json:"phone,omitempty"`
 
 	Email          string                 `json:"email,omitempty"`
@@ -36,36 +38,80 @@ type CustomerLinks struct {
 	Source      string `json:"source"`
 }
 
+// CustomerUpdate holds the fields of a customer to update with
+// CustomerService.UpdateWith. Unset fields are left as they are.
+type CustomerUpdate struct {
+	Name         *string                `json:"name,omitempty"`
+	Email        *string                `json:"email,omitempty"`
+	BusinessName *string                `json:"business_name,omitempty"`
+	Phone        *string                `json:"phone,omitempty"`
+	Ein          *string                `json:"ein,omitempty"`
+	SsnLast4     *string                `json:"ssn_last4,omitempty"`
+	DobMonth     *int                   `json:"dob_month,omitempty"`
+	DobYear      *int                   `json:"dob_year,omitempty"`
+	Address      *Address               `json:"address,omitempty"`
+	Meta         map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Validate checks the email address, the last digits of the SSN, the date of
+// birth and the meta keys.
+func (u *CustomerUpdate) Validate() error {
+	if u == nil {
+		return errNoUpdate
+	}
+	problems := updateProblems{}
+	if u.Email != nil && !strings.Contains(*u.Email, "@") {
+		problems["email"] = "must be an email address"
+	}
+	if u.SsnLast4 != nil && !isDigits(*u.SsnLast4, 4) {
+		problems["ssn_last4"] = "must be 4 digits"
+	}
+	if u.DobMonth != nil && (*u.DobMonth < 1 || *u.DobMonth > 12) {
+		problems["dob_month"] = "must be between 1 and 12"
+	}
+	if u.DobYear != nil && (*u.DobYear < 1900 || *u.DobYear > time.Now().Year()) {
+		problems["dob_year"] = "must be a year since 1900"
+	}
+	checkMeta(problems, u.Meta)
+	return problems.err()
+}
+
 // CustomerPage holds a paginated set of customers
 type CustomerPage struct {
 	Customers []Customer
 	*PaginationParams
 }
 
+type CustomerIterator = Iterator[Customer]
+
 type customerResponse struct {
-	Customers []Customer             `json:"customers"`
-	Links     customerResponseLinks  `json:"links"`
-	Meta      map[string]interface{} `json:"meta"`
+	Customers []Customer            `json:"customers"`
+	Links     customerResponseLinks `json:"links"`
+	Meta      *PaginationParams     `json:"meta"`
 }
 
 type customerResponseLinks struct {
-	BankAccounts     string `json:"customers.bank_accounts"`
-	CardHolds        string `json:"customers.card_holds"`
-	Cards            string `json:"customers.cards"`
-	Credits          string `json:"customers.credits"`
-	Debits           string `json:"customers.debits"`
-	Destination      string `json:"customers.destination"`
-	ExternalAccounts string `json:"customers.external_accounts"`
-	Orders           string `json:"customers.orders"`
-	Refunds          string `json:"customers.refunds"`
-	Reversals        string `json:"customers.reversals"`
-	Source           string `json:"customers.source"`
-	Transactions     string `json:"customers.transactions"`
+	BankAccounts     string `json:"customers.bank_accounts" template:"/customers/{customers.self}/bank_accounts"`
+	CardHolds        string `json:"customers.card_holds" template:"/customers/{customers.self}/card_holds"`
+	Cards            string `json:"customers.cards" template:"/customers/{customers.self}/cards"`
+	Credits          string `json:"customers.credits" template:"/customers/{customers.self}/credits"`
+	Debits           string `json:"customers.debits" template:"/customers/{customers.self}/debits"`
+	Destination      string `json:"customers.destination" template:"/resources/{customers.destination}"`
+	ExternalAccounts string `json:"customers.external_accounts" template:"/customers/{customers.self}/external_accounts"`
+	Orders           string `json:"customers.orders" template:"/customers/{customers.self}/orders"`
+	Refunds          string `json:"customers.refunds" template:"/customers/{customers.self}/refunds"`
+	Reversals        string `json:"customers.reversals" template:"/customers/{customers.self}/reversals"`
+	Source           string `json:"customers.source" template:"/resources/{customers.source}"`
+	Transactions     string `json:"customers.transactions" template:"/customers/{customers.self}/transactions"`
 }
 
 func (s *CustomerService) Create(customer *Customer) (*Customer, *http.Response, error) {
+	return s.CreateContext(context.Background(), customer)
+}
+
+func (s *CustomerService) CreateContext(ctx context.Context, customer *Customer) (*Customer, *http.Response, error) {
 	customerResponse := new(customerResponse)
-	httpResponse, err := s.client.POST("/customers", nil, customer, customerResponse)
+	httpResponse, err := s.client.POSTContext(ctx, "/customers", nil, customer, customerResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -73,8 +119,12 @@ func (s *CustomerService) Create(customer *Customer) (*Customer, *http.Response,
 }
 
 func (s *CustomerService) Delete(customerId string) (bool, *http.Response, error) {
+	return s.DeleteContext(context.Background(), customerId)
+}
+
+func (s *CustomerService) DeleteContext(ctx context.Context, customerId string) (bool, *http.Response, error) {
 	path := fmt.Sprintf("/customers/%v", customerId)
-	httpResponse, err := s.client.DELETE(path, nil, nil, nil)
+	httpResponse, err := s.client.DELETEContext(ctx, path, nil, nil, nil)
 	if err != nil {
 		return false, httpResponse, err
 	}
@@ -84,23 +134,86 @@ func (s *CustomerService) Delete(customerId string) (bool, *http.Response, error
 }
 
 func (s *CustomerService) List(args ...interface{}) (*CustomerPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *CustomerService) ListContext(ctx context.Context, args ...interface{}) (*CustomerPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/customers", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *CustomerService) ListWith(opts *ListOptions) (*CustomerPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *CustomerService) ListWithContext(ctx context.Context, opts *ListOptions) (*CustomerPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *CustomerService) list(ctx context.Context, path string, query map[string]interface{}) (*CustomerPage, *http.Response, error) {
 	customerResponse := new(customerResponse)
-	httpResponse, err := s.client.GET("/customers", query, nil, customerResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, customerResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &CustomerPage{
 		Customers:        customerResponse.Customers,
-		PaginationParams: NewPaginationParams(customerResponse.Meta),
+		PaginationParams: customerResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of customers at href, such as the Next or Previous
+// link of another page.
+func (s *CustomerService) FetchPage(href string) (*CustomerPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *CustomerService) FetchPageContext(ctx context.Context, href string) (*CustomerPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all customers, fetching pages as needed. args are
+// the same as for List.
+func (s *CustomerService) Iter(args ...interface{}) *CustomerIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *CustomerService) IterContext(ctx context.Context, args ...interface{}) *CustomerIterator {
+	return newIterator(ctx, "/customers", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *CustomerService) IterWith(opts *ListOptions) *CustomerIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *CustomerService) IterWithContext(ctx context.Context, opts *ListOptions) *CustomerIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *CustomerService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Customer, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Customers, page.PaginationParams, nil
+}
+
 func (s *CustomerService) Fetch(customerId string) (*Customer, *http.Response, error) {
+	return s.FetchContext(context.Background(), customerId)
+}
+
+func (s *CustomerService) FetchContext(ctx context.Context, customerId string) (*Customer, *http.Response, error) {
 	path := fmt.Sprintf("/customers/%v", customerId)
 	customerResponse := new(customerResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, customerResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, customerResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -108,9 +221,29 @@ func (s *CustomerService) Fetch(customerId string) (*Customer, *http.Response, e
 }
 
 func (s *CustomerService) Update(customerId string, params map[string]interface{}) (*Customer, *http.Response, error) {
+	return s.UpdateContext(context.Background(), customerId, params)
+}
+
+func (s *CustomerService) UpdateContext(ctx context.Context, customerId string, params map[string]interface{}) (*Customer, *http.Response, error) {
+	path := fmt.Sprintf("/customers/%v", customerId)
+	customerResponse := new(customerResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, customerResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &customerResponse.Customers[0], httpResponse, nil
+}
+
+// UpdateWith updates the fields of a customer set in update, once
+// update.Validate accepts them.
+func (s *CustomerService) UpdateWith(customerId string, update *CustomerUpdate) (*Customer, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), customerId, update)
+}
+
+func (s *CustomerService) UpdateWithContext(ctx context.Context, customerId string, update *CustomerUpdate) (*Customer, *http.Response, error) {
 	path := fmt.Sprintf("/customers/%v", customerId)
 	customerResponse := new(customerResponse)
-	httpResponse, err := s.client.PUT(path, nil, params, customerResponse)
+	httpResponse, err := s.client.putUpdate(ctx, path, update, customerResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -118,9 +251,396 @@ func (s *CustomerService) Update(customerId string, params map[string]interface{
 }
 
 func (s *CustomerService) AssociateWithCard(customerId, cardId string) (*Card, *http.Response, error) {
-	return s.client.Card.AssociateWithCustomer(cardId, customerId)
+	return s.AssociateWithCardContext(context.Background(), customerId, cardId)
+}
+
+func (s *CustomerService) AssociateWithCardContext(ctx context.Context, customerId, cardId string) (*Card, *http.Response, error) {
+	return s.client.Card.AssociateWithCustomerContext(ctx, cardId, customerId)
 }
 
 func (s *CustomerService) AssociateWithBankAccount(customerId, accountId string) (*BankAccount, *http.Response, error) {
-	return s.client.BankAccount.AssociateWithCustomer(accountId, customerId)
+	return s.AssociateWithBankAccountContext(context.Background(), customerId, accountId)
+}
+
+func (s *CustomerService) AssociateWithBankAccountContext(ctx context.Context, customerId, accountId string) (*BankAccount, *http.Response, error) {
+	return s.client.BankAccount.AssociateWithCustomerContext(ctx, accountId, customerId)
+}
+
+// ListCards lists the cards of a customer. args are the same as for List.
+func (s *CustomerService) ListCards(customerId string, args ...interface{}) (*CardPage, *http.Response, error) {
+	return s.ListCardsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) ListCardsContext(ctx context.Context, customerId string, args ...interface{}) (*CardPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/customers/%v/cards", customerId)
+	return s.client.Card.list(ctx, path, query)
+}
+
+// ListCardsWith is like ListCards, but takes typed options, which may be nil.
+func (s *CustomerService) ListCardsWith(customerId string, opts *ListOptions) (*CardPage, *http.Response, error) {
+	return s.ListCardsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) ListCardsWithContext(ctx context.Context, customerId string, opts *ListOptions) (*CardPage, *http.Response, error) {
+	return s.ListCardsContext(ctx, customerId, opts)
+}
+
+// IterCards returns an iterator over all cards of a customer.
+func (s *CustomerService) IterCards(customerId string, args ...interface{}) *CardIterator {
+	return s.IterCardsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) IterCardsContext(ctx context.Context, customerId string, args ...interface{}) *CardIterator {
+	path := fmt.Sprintf("/customers/%v/cards", customerId)
+	return newIterator(ctx, path, args, s.client.Card.fetchPage)
+}
+
+// IterCardsWith is like IterCards, but takes typed options, which may be nil.
+func (s *CustomerService) IterCardsWith(customerId string, opts *ListOptions) *CardIterator {
+	return s.IterCardsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) IterCardsWithContext(ctx context.Context, customerId string, opts *ListOptions) *CardIterator {
+	return s.IterCardsContext(ctx, customerId, opts)
+}
+
+// ListBankAccounts lists the bank accounts of a customer. args are the same as for List.
+func (s *CustomerService) ListBankAccounts(customerId string, args ...interface{}) (*BankAccountPage, *http.Response, error) {
+	return s.ListBankAccountsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) ListBankAccountsContext(ctx context.Context, customerId string, args ...interface{}) (*BankAccountPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/customers/%v/bank_accounts", customerId)
+	return s.client.BankAccount.list(ctx, path, query)
+}
+
+// ListBankAccountsWith is like ListBankAccounts, but takes typed options, which may be nil.
+func (s *CustomerService) ListBankAccountsWith(customerId string, opts *ListOptions) (*BankAccountPage, *http.Response, error) {
+	return s.ListBankAccountsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) ListBankAccountsWithContext(ctx context.Context, customerId string, opts *ListOptions) (*BankAccountPage, *http.Response, error) {
+	return s.ListBankAccountsContext(ctx, customerId, opts)
+}
+
+// IterBankAccounts returns an iterator over all bank accounts of a customer.
+func (s *CustomerService) IterBankAccounts(customerId string, args ...interface{}) *BankAccountIterator {
+	return s.IterBankAccountsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) IterBankAccountsContext(ctx context.Context, customerId string, args ...interface{}) *BankAccountIterator {
+	path := fmt.Sprintf("/customers/%v/bank_accounts", customerId)
+	return newIterator(ctx, path, args, s.client.BankAccount.fetchPage)
+}
+
+// IterBankAccountsWith is like IterBankAccounts, but takes typed options, which may be nil.
+func (s *CustomerService) IterBankAccountsWith(customerId string, opts *ListOptions) *BankAccountIterator {
+	return s.IterBankAccountsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) IterBankAccountsWithContext(ctx context.Context, customerId string, opts *ListOptions) *BankAccountIterator {
+	return s.IterBankAccountsContext(ctx, customerId, opts)
+}
+
+// ListDebits lists the debits of a customer. args are the same as for List.
+func (s *CustomerService) ListDebits(customerId string, args ...interface{}) (*DebitPage, *http.Response, error) {
+	return s.ListDebitsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) ListDebitsContext(ctx context.Context, customerId string, args ...interface{}) (*DebitPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/customers/%v/debits", customerId)
+	return s.client.Debit.list(ctx, path, query)
+}
+
+// ListDebitsWith is like ListDebits, but takes typed options, which may be nil.
+func (s *CustomerService) ListDebitsWith(customerId string, opts *ListOptions) (*DebitPage, *http.Response, error) {
+	return s.ListDebitsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) ListDebitsWithContext(ctx context.Context, customerId string, opts *ListOptions) (*DebitPage, *http.Response, error) {
+	return s.ListDebitsContext(ctx, customerId, opts)
+}
+
+// IterDebits returns an iterator over all debits of a customer.
+func (s *CustomerService) IterDebits(customerId string, args ...interface{}) *DebitIterator {
+	return s.IterDebitsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) IterDebitsContext(ctx context.Context, customerId string, args ...interface{}) *DebitIterator {
+	path := fmt.Sprintf("/customers/%v/debits", customerId)
+	return newIterator(ctx, path, args, s.client.Debit.fetchPage)
+}
+
+// IterDebitsWith is like IterDebits, but takes typed options, which may be nil.
+func (s *CustomerService) IterDebitsWith(customerId string, opts *ListOptions) *DebitIterator {
+	return s.IterDebitsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) IterDebitsWithContext(ctx context.Context, customerId string, opts *ListOptions) *DebitIterator {
+	return s.IterDebitsContext(ctx, customerId, opts)
+}
+
+// ListCredits lists the credits of a customer. args are the same as for List.
+func (s *CustomerService) ListCredits(customerId string, args ...interface{}) (*CreditPage, *http.Response, error) {
+	return s.ListCreditsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) ListCreditsContext(ctx context.Context, customerId string, args ...interface{}) (*CreditPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/customers/%v/credits", customerId)
+	return s.client.Credit.list(ctx, path, query)
+}
+
+// ListCreditsWith is like ListCredits, but takes typed options, which may be nil.
+func (s *CustomerService) ListCreditsWith(customerId string, opts *ListOptions) (*CreditPage, *http.Response, error) {
+	return s.ListCreditsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) ListCreditsWithContext(ctx context.Context, customerId string, opts *ListOptions) (*CreditPage, *http.Response, error) {
+	return s.ListCreditsContext(ctx, customerId, opts)
+}
+
+// IterCredits returns an iterator over all credits of a customer.
+func (s *CustomerService) IterCredits(customerId string, args ...interface{}) *CreditIterator {
+	return s.IterCreditsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) IterCreditsContext(ctx context.Context, customerId string, args ...interface{}) *CreditIterator {
+	path := fmt.Sprintf("/customers/%v/credits", customerId)
+	return newIterator(ctx, path, args, s.client.Credit.fetchPage)
+}
+
+// IterCreditsWith is like IterCredits, but takes typed options, which may be nil.
+func (s *CustomerService) IterCreditsWith(customerId string, opts *ListOptions) *CreditIterator {
+	return s.IterCreditsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) IterCreditsWithContext(ctx context.Context, customerId string, opts *ListOptions) *CreditIterator {
+	return s.IterCreditsContext(ctx, customerId, opts)
+}
+
+// ListOrders lists the orders of a customer. args are the same as for List.
+func (s *CustomerService) ListOrders(customerId string, args ...interface{}) (*OrderPage, *http.Response, error) {
+	return s.ListOrdersContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) ListOrdersContext(ctx context.Context, customerId string, args ...interface{}) (*OrderPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/customers/%v/orders", customerId)
+	return s.client.Order.list(ctx, path, query)
+}
+
+// ListOrdersWith is like ListOrders, but takes typed options, which may be nil.
+func (s *CustomerService) ListOrdersWith(customerId string, opts *ListOptions) (*OrderPage, *http.Response, error) {
+	return s.ListOrdersContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) ListOrdersWithContext(ctx context.Context, customerId string, opts *ListOptions) (*OrderPage, *http.Response, error) {
+	return s.ListOrdersContext(ctx, customerId, opts)
+}
+
+// IterOrders returns an iterator over all orders of a customer.
+func (s *CustomerService) IterOrders(customerId string, args ...interface{}) *OrderIterator {
+	return s.IterOrdersContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) IterOrdersContext(ctx context.Context, customerId string, args ...interface{}) *OrderIterator {
+	path := fmt.Sprintf("/customers/%v/orders", customerId)
+	return newIterator(ctx, path, args, s.client.Order.fetchPage)
+}
+
+// IterOrdersWith is like IterOrders, but takes typed options, which may be nil.
+func (s *CustomerService) IterOrdersWith(customerId string, opts *ListOptions) *OrderIterator {
+	return s.IterOrdersContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) IterOrdersWithContext(ctx context.Context, customerId string, opts *ListOptions) *OrderIterator {
+	return s.IterOrdersContext(ctx, customerId, opts)
+}
+
+// ListRefunds lists the refunds of a customer. args are the same as for List.
+func (s *CustomerService) ListRefunds(customerId string, args ...interface{}) (*RefundPage, *http.Response, error) {
+	return s.ListRefundsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) ListRefundsContext(ctx context.Context, customerId string, args ...interface{}) (*RefundPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/customers/%v/refunds", customerId)
+	return s.client.Refund.list(ctx, path, query)
+}
+
+// ListRefundsWith is like ListRefunds, but takes typed options, which may be nil.
+func (s *CustomerService) ListRefundsWith(customerId string, opts *ListOptions) (*RefundPage, *http.Response, error) {
+	return s.ListRefundsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) ListRefundsWithContext(ctx context.Context, customerId string, opts *ListOptions) (*RefundPage, *http.Response, error) {
+	return s.ListRefundsContext(ctx, customerId, opts)
+}
+
+// IterRefunds returns an iterator over all refunds of a customer.
+func (s *CustomerService) IterRefunds(customerId string, args ...interface{}) *RefundIterator {
+	return s.IterRefundsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) IterRefundsContext(ctx context.Context, customerId string, args ...interface{}) *RefundIterator {
+	path := fmt.Sprintf("/customers/%v/refunds", customerId)
+	return newIterator(ctx, path, args, s.client.Refund.fetchPage)
+}
+
+// IterRefundsWith is like IterRefunds, but takes typed options, which may be nil.
+func (s *CustomerService) IterRefundsWith(customerId string, opts *ListOptions) *RefundIterator {
+	return s.IterRefundsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) IterRefundsWithContext(ctx context.Context, customerId string, opts *ListOptions) *RefundIterator {
+	return s.IterRefundsContext(ctx, customerId, opts)
+}
+
+// ListReversals lists the reversals of a customer. args are the same as for List.
+func (s *CustomerService) ListReversals(customerId string, args ...interface{}) (*ReversalPage, *http.Response, error) {
+	return s.ListReversalsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) ListReversalsContext(ctx context.Context, customerId string, args ...interface{}) (*ReversalPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/customers/%v/reversals", customerId)
+	return s.client.Reversal.list(ctx, path, query)
+}
+
+// ListReversalsWith is like ListReversals, but takes typed options, which may be nil.
+func (s *CustomerService) ListReversalsWith(customerId string, opts *ListOptions) (*ReversalPage, *http.Response, error) {
+	return s.ListReversalsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) ListReversalsWithContext(ctx context.Context, customerId string, opts *ListOptions) (*ReversalPage, *http.Response, error) {
+	return s.ListReversalsContext(ctx, customerId, opts)
+}
+
+// IterReversals returns an iterator over all reversals of a customer.
+func (s *CustomerService) IterReversals(customerId string, args ...interface{}) *ReversalIterator {
+	return s.IterReversalsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) IterReversalsContext(ctx context.Context, customerId string, args ...interface{}) *ReversalIterator {
+	path := fmt.Sprintf("/customers/%v/reversals", customerId)
+	return newIterator(ctx, path, args, s.client.Reversal.fetchPage)
+}
+
+// IterReversalsWith is like IterReversals, but takes typed options, which may be nil.
+func (s *CustomerService) IterReversalsWith(customerId string, opts *ListOptions) *ReversalIterator {
+	return s.IterReversalsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) IterReversalsWithContext(ctx context.Context, customerId string, opts *ListOptions) *ReversalIterator {
+	return s.IterReversalsContext(ctx, customerId, opts)
+}
+
+// ListCardHolds lists the card holds of a customer. args are the same as for List.
+func (s *CustomerService) ListCardHolds(customerId string, args ...interface{}) (*CardHoldPage, *http.Response, error) {
+	return s.ListCardHoldsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) ListCardHoldsContext(ctx context.Context, customerId string, args ...interface{}) (*CardHoldPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/customers/%v/card_holds", customerId)
+	return s.client.CardHold.list(ctx, path, query)
+}
+
+// ListCardHoldsWith is like ListCardHolds, but takes typed options, which may be nil.
+func (s *CustomerService) ListCardHoldsWith(customerId string, opts *ListOptions) (*CardHoldPage, *http.Response, error) {
+	return s.ListCardHoldsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) ListCardHoldsWithContext(ctx context.Context, customerId string, opts *ListOptions) (*CardHoldPage, *http.Response, error) {
+	return s.ListCardHoldsContext(ctx, customerId, opts)
+}
+
+// IterCardHolds returns an iterator over all card holds of a customer.
+func (s *CustomerService) IterCardHolds(customerId string, args ...interface{}) *CardHoldIterator {
+	return s.IterCardHoldsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) IterCardHoldsContext(ctx context.Context, customerId string, args ...interface{}) *CardHoldIterator {
+	path := fmt.Sprintf("/customers/%v/card_holds", customerId)
+	return newIterator(ctx, path, args, s.client.CardHold.fetchPage)
+}
+
+// IterCardHoldsWith is like IterCardHolds, but takes typed options, which may be nil.
+func (s *CustomerService) IterCardHoldsWith(customerId string, opts *ListOptions) *CardHoldIterator {
+	return s.IterCardHoldsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) IterCardHoldsWithContext(ctx context.Context, customerId string, opts *ListOptions) *CardHoldIterator {
+	return s.IterCardHoldsContext(ctx, customerId, opts)
+}
+
+// ListTransactions lists all the debits, credits, refunds, reversals and card
+// holds of a customer together. args are the same as for List.
+func (s *CustomerService) ListTransactions(customerId string, args ...interface{}) (*TransactionPage, *http.Response, error) {
+	return s.ListTransactionsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) ListTransactionsContext(ctx context.Context, customerId string, args ...interface{}) (*TransactionPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/customers/%v/transactions", customerId)
+	return s.client.Transaction.list(ctx, path, query)
+}
+
+// ListTransactionsWith is like ListTransactions, but takes typed options, which may be nil.
+func (s *CustomerService) ListTransactionsWith(customerId string, opts *ListOptions) (*TransactionPage, *http.Response, error) {
+	return s.ListTransactionsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) ListTransactionsWithContext(ctx context.Context, customerId string, opts *ListOptions) (*TransactionPage, *http.Response, error) {
+	return s.ListTransactionsContext(ctx, customerId, opts)
+}
+
+// IterTransactions returns an iterator over all transactions of a customer.
+func (s *CustomerService) IterTransactions(customerId string, args ...interface{}) *TransactionIterator {
+	return s.IterTransactionsContext(context.Background(), customerId, args...)
+}
+
+func (s *CustomerService) IterTransactionsContext(ctx context.Context, customerId string, args ...interface{}) *TransactionIterator {
+	path := fmt.Sprintf("/customers/%v/transactions", customerId)
+	return newIterator(ctx, path, args, s.client.Transaction.fetchPage)
+}
+
+// IterTransactionsWith is like IterTransactions, but takes typed options, which may be nil.
+func (s *CustomerService) IterTransactionsWith(customerId string, opts *ListOptions) *TransactionIterator {
+	return s.IterTransactionsContext(context.Background(), customerId, opts)
+}
+
+func (s *CustomerService) IterTransactionsWithContext(ctx context.Context, customerId string, opts *ListOptions) *TransactionIterator {
+	return s.IterTransactionsContext(ctx, customerId, opts)
 }
@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -38,20 +39,20 @@ type DebitLinks struct {
 }
 
 type debitResponse struct {
-	Debits []Debit                `json:"debits"`
-	Links  *debitResponseLinks    `json:"links,omitempty"`
-	Meta   map[string]interface{} `json:"meta,omitempty"`
+	Debits []Debit             `json:"debits"`
+	Links  *debitResponseLinks `json:"links,omitempty"`
+	Meta   *PaginationParams   `json:"meta,omitempty"`
 }
 
 type DebitRequest debitResponse
 
 type debitResponseLinks struct {
-	Customer string `json:"debits.customer"`
-	Dispute  string `json:"debits.dispute"`
-	Events   string `json:"debits.events"`
-	Order    string `json:"debits.order"`
-	Refunds  string `json:"debits.refunds"`
-	Source   string `json:"debits.source"`
+	Customer string `json:"debits.customer" template:"/customers/{debits.customer}"`
+	Dispute  string `json:"debits.dispute" template:"/disputes/{debits.dispute}"`
+	Events   string `json:"debits.events" template:"/debits/{debits.self}/events"`
+	Order    string `json:"debits.order" template:"/orders/{debits.order}"`
+	Refunds  string `json:"debits.refunds" template:"/debits/{debits.self}/refunds"`
+	Source   string `json:"debits.source" template:"/resources/{debits.source}"`
 }
 
 type DebitPage struct {
@@ -59,10 +60,16 @@ type DebitPage struct {
 	*PaginationParams
 }
 
+type DebitIterator = Iterator[Debit]
+
 func (s *DebitService) Fetch(debitId string) (*Debit, *http.Response, error) {
+	return s.FetchContext(context.Background(), debitId)
+}
+
+func (s *DebitService) FetchContext(ctx context.Context, debitId string) (*Debit, *http.Response, error) {
 	path := fmt.Sprintf("/debits/%v", debitId)
 	debitResponse := new(debitResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, debitResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, debitResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -70,23 +77,102 @@ func (s *DebitService) Fetch(debitId string) (*Debit, *http.Response, error) {
 }
 
 func (s *DebitService) List(args ...interface{}) (*DebitPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *DebitService) ListContext(ctx context.Context, args ...interface{}) (*DebitPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/debits", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *DebitService) ListWith(opts *ListOptions) (*DebitPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *DebitService) ListWithContext(ctx context.Context, opts *ListOptions) (*DebitPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *DebitService) list(ctx context.Context, path string, query map[string]interface{}) (*DebitPage, *http.Response, error) {
 	debitResponse := new(debitResponse)
-	httpResponse, err := s.client.GET("/debits", query, nil, debitResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, debitResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &DebitPage{
 		Debits:           debitResponse.Debits,
-		PaginationParams: NewPaginationParams(debitResponse.Meta),
+		PaginationParams: debitResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of debits at href, such as the Next or Previous
+// link of another page.
+func (s *DebitService) FetchPage(href string) (*DebitPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *DebitService) FetchPageContext(ctx context.Context, href string) (*DebitPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all debits, fetching pages as needed. args are
+// the same as for List.
+func (s *DebitService) Iter(args ...interface{}) *DebitIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *DebitService) IterContext(ctx context.Context, args ...interface{}) *DebitIterator {
+	return newIterator(ctx, "/debits", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *DebitService) IterWith(opts *ListOptions) *DebitIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *DebitService) IterWithContext(ctx context.Context, opts *ListOptions) *DebitIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *DebitService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Debit, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Debits, page.PaginationParams, nil
+}
+
 func (s *DebitService) Update(debitId string, params map[string]interface{}) (*Debit, *http.Response, error) {
+	return s.UpdateContext(context.Background(), debitId, params)
+}
+
+func (s *DebitService) UpdateContext(ctx context.Context, debitId string, params map[string]interface{}) (*Debit, *http.Response, error) {
 	path := fmt.Sprintf("/debits/%v", debitId)
 	debitResponse := new(debitResponse)
-	httpResponse, err := s.client.PUT(path, nil, params, debitResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, debitResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &debitResponse.Debits[0], httpResponse, nil
+}
+
+// UpdateWith updates the fields of a debit set in update, once
+// update.Validate accepts them.
+func (s *DebitService) UpdateWith(debitId string, update *DebitUpdate) (*Debit, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), debitId, update)
+}
+
+func (s *DebitService) UpdateWithContext(ctx context.Context, debitId string, update *DebitUpdate) (*Debit, *http.Response, error) {
+	path := fmt.Sprintf("/debits/%v", debitId)
+	debitResponse := new(debitResponse)
+	httpResponse, err := s.client.putUpdate(ctx, path, update, debitResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -94,11 +180,28 @@ func (s *DebitService) Update(debitId string, params map[string]interface{}) (*D
 }
 
 func (s *DebitService) Refund(debitId string, refund *Refund) (*Refund, *http.Response, error) {
+	return s.RefundContext(context.Background(), debitId, refund)
+}
+
+func (s *DebitService) RefundContext(ctx context.Context, debitId string, refund *Refund) (*Refund, *http.Response, error) {
 	path := fmt.Sprintf("/debits/%v/refunds", debitId)
 	refundResponse := new(refundResponse)
-	httpResponse, err := s.client.POST(path, nil, refund, refundResponse)
+	httpResponse, err := s.client.idempotentPOSTContext(ctx, path, nil, refund, refundResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &refundResponse.Refunds[0], httpResponse, err
 }
+
+// FetchSource fetches the card or bank account the debit took money from.
+func (s *DebitService) FetchSource(debit *Debit) (FundingInstrument, *http.Response, error) {
+	return s.FetchSourceContext(context.Background(), debit)
+}
+
+func (s *DebitService) FetchSourceContext(ctx context.Context, debit *Debit) (FundingInstrument, *http.Response, error) {
+	href, err := LinkHref("debits.source", debit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.client.FundingInstrument.FetchContext(ctx, href)
+}
@@ -1,8 +1,11 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -14,7 +17,7 @@ type DisputeService struct {
 type Dispute struct {
 	Amount      int               `json:"amount"`
 	Currency    string            `json:"currency,omitempty"`
-	Id          string            `json:"id,omitemptry"`
+	Id          string            `json:"id,omitempty"`
 	Href        string            `json:"href,omitempty"`
 	Status      string            `json:"status,omitempty"`
 	Links       *DisputeLinks     `json:"links,omitempty"`
@@ -27,18 +30,18 @@ type Dispute struct {
 }
 
 type DisputeLinks struct {
-	Transaction string `json:"transaction"`
+	Transaction string `json:"transaction"` // The disputed debit
 }
 
 type disputeResponse struct {
-	Disputes []Dispute              `json:"disputes"`
-	Links    *disputeResponseLinks  `json:"links"`
-	Meta     map[string]interface{} `json:"meta"`
+	Disputes []Dispute             `json:"disputes"`
+	Links    *disputeResponseLinks `json:"links"`
+	Meta     *PaginationParams     `json:"meta"`
 }
 
 type disputeResponseLinks struct {
-	Events     string `json:"disputes.events"`
-	Transation string `json:"disputes.transaction"`
+	Events      string `json:"disputes.events" template:"/disputes/{disputes.self}/events"`
+	Transaction string `json:"disputes.transaction" template:"/resources/{disputes.transaction}"`
 }
 
 type DisputePage struct {
@@ -46,10 +49,16 @@ type DisputePage struct {
 	*PaginationParams
 }
 
+type DisputeIterator = Iterator[Dispute]
+
 func (s *DisputeService) Fetch(disputeId string) (*Dispute, *http.Response, error) {
+	return s.FetchContext(context.Background(), disputeId)
+}
+
+func (s *DisputeService) FetchContext(ctx context.Context, disputeId string) (*Dispute, *http.Response, error) {
 	path := fmt.Sprintf("/disputes/%v", disputeId)
 	disputeResponse := new(disputeResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, disputeResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, disputeResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -57,15 +66,169 @@ func (s *DisputeService) Fetch(disputeId string) (*Dispute, *http.Response, erro
 }
 
 func (s *DisputeService) List(args ...interface{}) (*DisputePage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *DisputeService) ListContext(ctx context.Context, args ...interface{}) (*DisputePage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/disputes", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *DisputeService) ListWith(opts *ListOptions) (*DisputePage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *DisputeService) ListWithContext(ctx context.Context, opts *ListOptions) (*DisputePage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *DisputeService) list(ctx context.Context, path string, query map[string]interface{}) (*DisputePage, *http.Response, error) {
 	disputeResponse := new(disputeResponse)
-	httpResponse, err := s.client.GET("/disputes", query, nil, disputeResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, disputeResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &DisputePage{
 		Disputes:         disputeResponse.Disputes,
-		PaginationParams: NewPaginationParams(disputeResponse.Meta),
+		PaginationParams: disputeResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
+
+// FetchPage fetches the page of disputes at href, such as the Next or Previous
+// link of another page.
+func (s *DisputeService) FetchPage(href string) (*DisputePage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *DisputeService) FetchPageContext(ctx context.Context, href string) (*DisputePage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all disputes, fetching pages as needed. args are
+// the same as for List.
+func (s *DisputeService) Iter(args ...interface{}) *DisputeIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *DisputeService) IterContext(ctx context.Context, args ...interface{}) *DisputeIterator {
+	return newIterator(ctx, "/disputes", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *DisputeService) IterWith(opts *ListOptions) *DisputeIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *DisputeService) IterWithContext(ctx context.Context, opts *ListOptions) *DisputeIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *DisputeService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Dispute, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Disputes, page.PaginationParams, nil
+}
+
+// ListByStatus lists the disputes with the given status (Pending, Won or
+// Lost). args are the same as for List, but must not filter by status.
+func (s *DisputeService) ListByStatus(status string, args ...interface{}) (*DisputePage, *http.Response, error) {
+	return s.ListByStatusContext(context.Background(), status, args...)
+}
+
+func (s *DisputeService) ListByStatusContext(ctx context.Context, status string, args ...interface{}) (*DisputePage, *http.Response, error) {
+	switch status {
+	case Pending, Won, Lost:
+	default:
+		return nil, nil, fmt.Errorf("balanced: invalid dispute status %q; want %q, %q or %q", status, Pending, Won, Lost)
+	}
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key := range query {
+		if strings.SplitN(key, "[", 2)[0] == "status" {
+			return nil, nil, fmt.Errorf("balanced: invalid list options: %v conflicts with the status argument", key)
+		}
+	}
+	query["status"] = status
+	return s.list(ctx, "/disputes", query)
+}
+
+// ListByStatusWith is like ListByStatus, but takes typed options, which may be nil.
+func (s *DisputeService) ListByStatusWith(status string, opts *ListOptions) (*DisputePage, *http.Response, error) {
+	return s.ListByStatusContext(context.Background(), status, opts)
+}
+
+func (s *DisputeService) ListByStatusWithContext(ctx context.Context, status string, opts *ListOptions) (*DisputePage, *http.Response, error) {
+	return s.ListByStatusContext(ctx, status, opts)
+}
+
+// ListPendingByDeadline returns every pending dispute, soonest RespondBy
+// deadline first, so the ones needing attention come first. Disputes whose
+// deadline has passed are included; IsOverdue tells them apart. Disputes
+// without a deadline come last.
+func (s *DisputeService) ListPendingByDeadline() ([]Dispute, error) {
+	return s.ListPendingByDeadlineContext(context.Background())
+}
+
+func (s *DisputeService) ListPendingByDeadlineContext(ctx context.Context) ([]Dispute, error) {
+	var disputes []Dispute
+	it := s.IterContext(ctx, &ListOptions{Status: []string{Pending}})
+	for it.Next() {
+		disputes = append(disputes, *it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(disputes, func(i, j int) bool {
+		a, b := disputes[i].RespondBy, disputes[j].RespondBy
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.Before(*b)
+	})
+	return disputes, nil
+}
+
+// FetchTransaction fetches the debit that the dispute is about.
+func (s *DisputeService) FetchTransaction(dispute *Dispute) (*Debit, *http.Response, error) {
+	return s.FetchTransactionContext(context.Background(), dispute)
+}
+
+func (s *DisputeService) FetchTransactionContext(ctx context.Context, dispute *Dispute) (*Debit, *http.Response, error) {
+	if dispute.Links == nil || dispute.Links.Transaction == "" {
+		return nil, nil, fmt.Errorf("balanced: dispute %v has no transaction link", dispute.Id)
+	}
+	return s.client.Debit.FetchContext(ctx, dispute.Links.Transaction)
+}
+
+// NeedsResponse reports whether the dispute is pending and its RespondBy
+// deadline, if any, has not passed at time now.
+func (d *Dispute) NeedsResponse(now time.Time) bool {
+	return d.Status == Pending && !d.IsOverdue(now)
+}
+
+// IsOverdue reports whether the dispute is still pending after its RespondBy
+// deadline at time now.
+func (d *Dispute) IsOverdue(now time.Time) bool {
+	return d.Status == Pending && d.RespondBy != nil && now.After(*d.RespondBy)
+}
+
+// TimeToRespond returns how long remains at time now until the RespondBy
+// deadline; it is negative once the deadline has passed and 0 when the
+// dispute has no deadline.
+func (d *Dispute) TimeToRespond(now time.Time) time.Duration {
+	if d.RespondBy == nil {
+		return 0
+	}
+	return d.RespondBy.Sub(now)
+}
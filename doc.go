@@ -4,5 +4,53 @@
 /*
 Package balanced provides a client implementation for the Balanced Payments
 API.
+
+List methods take a ListOptions to filter and sort the results, or nil, in
+their variants suffixed with With:
+
+	page, _, err := client.Debit.ListWith(&balanced.ListOptions{
+		Limit:  20,
+		Sort:   "created_at,desc",
+		Status: []string{balanced.Failed},
+	})
+
+The variadic forms (e.g., DebitService.List), which also accept an offset and
+a limit or a map of raw query parameters, are kept for compatibility; their
+arguments are only checked when called.
+
+The returned page can be followed with the FetchPage method of the service
+(e.g., client.Debit.FetchPage(page.Next)), or every item can be visited with
+an iterator, which fetches pages as needed:
+
+	for debit, err := range client.Debit.IterWith(opts).All() {
+		...
+	}
+
+Every service method has a variant suffixed with Context (e.g.,
+CardService.CreateContext) that takes a context.Context as its first argument.
+The request is aborted when the context is canceled or its deadline passes,
+and the context's error is returned. The methods without a context use
+context.Background().
+
+Calls that move money (CardService.Charge, CardService.Credit,
+BankAccountService.Debit, BankAccountService.Credit, CardHoldService.Capture,
+DebitService.Refund and ReversalService.Create, along with the methods built
+on them) send an Idempotency-Key header, which is kept when the request is
+retried. Pass a context from WithIdempotencyKey to choose the key yourself,
+so that a call can be repeated with the same key. Whether money is then moved
+only once depends on the API honouring the key; with WithIdempotencyCache, the
+client also replays the responses to the calls that succeeded, but only within
+the process.
+
+Resources link to each other by id. Client.FollowLink fetches the target of a
+link named as in the API's responses, decoded to its type:
+
+	source, _, err := client.FollowLink("debits.source", debit)
+	switch source := source.(type) {
+	case *balanced.Card:
+		...
+	case *balanced.BankAccount:
+		...
+	}
 */
 package balanced
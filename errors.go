@@ -0,0 +1,110 @@
+package balanced
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Kinds of failures reported by the Balanced API. An *ErrorResponse matches
+// them with errors.Is, e.g.:
+//
+//	if errors.Is(err, balanced.ErrCardDeclined) { ... }
+var (
+	ErrCardDeclined                    = errors.New("balanced: card declined")
+	ErrInsufficientFunds               = errors.New("balanced: insufficient funds")
+	ErrFundingSourceNotDebitable       = errors.New("balanced: funding source not debitable")
+	ErrFundingDestinationNotCreditable = errors.New("balanced: funding destination not creditable")
+	ErrVerificationFailed              = errors.New("balanced: verification failed")
+	ErrValidation                      = errors.New("balanced: invalid request")
+	ErrUnauthorized                    = errors.New("balanced: unauthorized")
+	ErrNotFound                        = errors.New("balanced: not found")
+	ErrRateLimited                     = errors.New("balanced: rate limited")
+	ErrServer                          = errors.New("balanced: server error")
+)
+
+// categoryErrors maps Balanced category codes to the kind of failure they
+// report.
+var categoryErrors = map[string]error{
+	"card-declined":                           ErrCardDeclined,
+	"authorization-failed":                    ErrCardDeclined,
+	"insufficient-funds":                      ErrInsufficientFunds,
+	"funding-source-not-debitable":            ErrFundingSourceNotDebitable,
+	"funding-destination-not-creditable":      ErrFundingDestinationNotCreditable,
+	"bank-account-authentication-failed":      ErrVerificationFailed,
+	"bank-account-authentication-forbidden":   ErrVerificationFailed,
+	"bank-account-authentication-not-pending": ErrVerificationFailed,
+	"authentication-required":                 ErrUnauthorized,
+	"not-found":                               ErrNotFound,
+}
+
+// Is reports whether the status code of the response matches target. The
+// individual errors are matched through Unwrap.
+func (r *ErrorResponse) Is(target error) bool {
+	code := r.Response.StatusCode
+	switch target {
+	case ErrValidation:
+		return code == http.StatusBadRequest
+	case ErrUnauthorized:
+		return code == http.StatusUnauthorized || code == http.StatusForbidden
+	case ErrNotFound:
+		return code == http.StatusNotFound
+	case ErrRateLimited:
+		return code == http.StatusTooManyRequests
+	case ErrServer:
+		return code >= 500
+	}
+	return false
+}
+
+// Unwrap returns each of r.Errors, as a *ValidationError when it is about
+// invalid request parameters and as an *ErrorResponseError otherwise.
+func (r *ErrorResponse) Unwrap() []error {
+	errs := make([]error, len(r.Errors))
+	for i := range r.Errors {
+		e := &r.Errors[i]
+		if e.isValidation() {
+			errs[i] = newValidationError(e)
+		} else {
+			errs[i] = e
+		}
+	}
+	return errs
+}
+
+func (e *ErrorResponseError) Error() string {
+	return fmt.Sprintf("%v: %v", e.CategoryCode, e.Description)
+}
+
+// Is reports whether the category code of e matches target.
+func (e *ErrorResponseError) Is(target error) bool {
+	if err, ok := categoryErrors[e.CategoryCode]; ok && err == target {
+		return true
+	}
+	return target == ErrValidation && e.isValidation()
+}
+
+func (e *ErrorResponseError) isValidation() bool {
+	return e.CategoryType == "request" || e.StatusCode == http.StatusBadRequest
+}
+
+// ValidationError is an error about invalid request parameters.
+type ValidationError struct {
+	*ErrorResponseError
+
+	// Fields maps each invalid parameter to what is wrong with it, e.g.
+	// "amount" => "must be >= 50".
+	Fields map[string]string
+}
+
+func newValidationError(e *ErrorResponseError) *ValidationError {
+	fields := make(map[string]string, len(e.Extras))
+	for field, problem := range e.Extras {
+		fields[field] = fmt.Sprint(problem)
+	}
+	return &ValidationError{ErrorResponseError: e, Fields: fields}
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.ErrorResponseError
+}
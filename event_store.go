@@ -0,0 +1,259 @@
+package balanced
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// An EventStore records the events a WebhookHandler has processed, so that
+// each event is handled at most once although Balanced retries callbacks.
+type EventStore interface {
+	// Claim records eventId as being processed. It reports false if eventId
+	// was claimed before and not released since.
+	Claim(ctx context.Context, eventId string) (bool, error)
+
+	// Release forgets eventId, e.g. because handling it failed, so that it can
+	// be claimed again.
+	Release(ctx context.Context, eventId string) error
+}
+
+// WithEventStore makes the handler claim each event in store before handling
+// it, and skip the events already claimed. If handling an event fails, it is
+// released so that the callback can be retried.
+func WithEventStore(store EventStore) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.store = store
+	}
+}
+
+// eventClaims holds the expiry of the claimed events by id. Expired claims
+// are ignored when looked up, and dropped once per ttl.
+type eventClaims struct {
+	ttl       time.Duration
+	expires   map[string]time.Time
+	nextPrune time.Time
+}
+
+func newEventClaims(ttl time.Duration) eventClaims {
+	return eventClaims{ttl: ttl, expires: make(map[string]time.Time), nextPrune: time.Now().Add(ttl)}
+}
+
+// claim records eventId unless it is claimed and has not expired, and returns
+// the expiry of the new claim.
+func (c *eventClaims) claim(eventId string) (time.Time, bool) {
+	now := time.Now()
+	if !now.Before(c.nextPrune) {
+		c.prune(now)
+	}
+	if expiresAt, ok := c.expires[eventId]; ok && now.Before(expiresAt) {
+		return time.Time{}, false
+	}
+	expiresAt := now.Add(c.ttl)
+	c.expires[eventId] = expiresAt
+	return expiresAt, true
+}
+
+// prune drops the expired claims.
+func (c *eventClaims) prune(now time.Time) {
+	for id, expiresAt := range c.expires {
+		if !now.Before(expiresAt) {
+			delete(c.expires, id)
+		}
+	}
+	c.nextPrune = now.Add(c.ttl)
+}
+
+// MemoryEventStore is an EventStore keeping the ids of events in memory for a
+// limited time.
+type MemoryEventStore struct {
+	mu     sync.Mutex
+	claims eventClaims
+}
+
+// NewMemoryEventStore returns a store forgetting events ttl after they were
+// claimed. ttl should exceed the time over which Balanced retries callbacks.
+func NewMemoryEventStore(ttl time.Duration) *MemoryEventStore {
+	return &MemoryEventStore{claims: newEventClaims(ttl)}
+}
+
+func (s *MemoryEventStore) Claim(ctx context.Context, eventId string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, claimed := s.claims.claim(eventId)
+	return claimed, nil
+}
+
+func (s *MemoryEventStore) Release(ctx context.Context, eventId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claims.expires, eventId)
+	return nil
+}
+
+// minCompactLines is the number of lines below which a FileEventStore does
+// not compact its file.
+const minCompactLines = 1000
+
+// FileEventStore is an EventStore keeping the ids of events in a file for a
+// limited time, so that they are remembered across restarts. Each claim and
+// release is appended to the file as a line of JSON and synced to disk; the
+// file is rewritten with the live claims only when most of its lines are
+// stale. It must not be shared between processes.
+type FileEventStore struct {
+	path string
+
+	mu     sync.Mutex
+	claims eventClaims
+	lines  int // number of lines in the file
+}
+
+// fileEventRecord is a line of the file of a FileEventStore: the claim of an
+// event until ExpiresAt, or its release if ExpiresAt is nil.
+type fileEventRecord struct {
+	Id        string     `json:"id"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// NewFileEventStore returns a store kept in the file at path, which is
+// created if it does not exist. Events are forgotten ttl after they were
+// claimed.
+func NewFileEventStore(path string, ttl time.Duration) (*FileEventStore, error) {
+	s := &FileEventStore{path: path, claims: newEventClaims(ttl)}
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var record fileEventRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// A line cut short by a crash while it was appended; the
+			// rewrite below drops it.
+			continue
+		}
+		if record.ExpiresAt == nil {
+			delete(s.claims.expires, record.Id)
+		} else {
+			s.claims.expires[record.Id] = *record.ExpiresAt
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileEventStore) Claim(ctx context.Context, eventId string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, claimed := s.claims.claim(eventId)
+	if !claimed {
+		return false, nil
+	}
+	if err := s.append(fileEventRecord{Id: eventId, ExpiresAt: &expiresAt}); err != nil {
+		delete(s.claims.expires, eventId)
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *FileEventStore) Release(ctx context.Context, eventId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.claims.expires[eventId]; !ok {
+		return nil
+	}
+	delete(s.claims.expires, eventId)
+	return s.append(fileEventRecord{Id: eventId})
+}
+
+// append writes record at the end of the file and syncs it, compacting the
+// file if most of its lines are stale.
+func (s *FileEventStore) append(record fileEventRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	s.lines++
+	if s.lines >= minCompactLines && s.lines > 2*len(s.claims.expires) {
+		return s.compact()
+	}
+	return nil
+}
+
+// compact rewrites the file with the claims that have not expired.
+func (s *FileEventStore) compact() error {
+	s.claims.prune(time.Now())
+	var buf bytes.Buffer
+	for id, expiresAt := range s.claims.expires {
+		data, err := json.Marshal(fileEventRecord{Id: id, ExpiresAt: &expiresAt})
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	if err := writeFileAtomic(s.path, buf.Bytes()); err != nil {
+		return err
+	}
+	s.lines = len(s.claims.expires)
+	return nil
+}
+
+// writeFileAtomic writes data to the file at path, replacing the file only
+// once data is completely written and synced to disk, so that a crash leaves
+// either the old or the new file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := ioutil.TempFile(dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	// Sync the directory too, so that the rename itself survives a crash.
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
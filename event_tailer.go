@@ -0,0 +1,290 @@
+package balanced
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMinPollInterval = 5 * time.Second
+	defaultMaxPollInterval = 5 * time.Minute
+
+	// tailPageSize is the number of events an EventTailer fetches per
+	// request.
+	tailPageSize = 100
+)
+
+// An EventCursor is the position of an EventTailer in the events: the time at
+// which the last emitted event occurred, and the ids of the emitted events
+// that occurred at that time, as several events may share it.
+type EventCursor struct {
+	OccurredAt time.Time `json:"occurred_at"`
+	Ids        []string  `json:"ids"`
+}
+
+// passed reports whether event was emitted, or occurred before the last
+// event emitted.
+func (c *EventCursor) passed(event *Event) bool {
+	if event.OccurredAt.Before(c.OccurredAt) {
+		return true
+	}
+	if !event.OccurredAt.Equal(c.OccurredAt) {
+		return false
+	}
+	for _, id := range c.Ids {
+		if id == event.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// advance moves the cursor past event.
+func (c *EventCursor) advance(event *Event) {
+	if event.OccurredAt.Equal(c.OccurredAt) {
+		c.Ids = append(c.Ids, event.Id)
+		return
+	}
+	c.OccurredAt, c.Ids = *event.OccurredAt, []string{event.Id}
+}
+
+// A CursorStore persists the cursor of an EventTailer, so that it resumes
+// where it stopped when restarted.
+type CursorStore interface {
+	// Load returns the cursor saved last, or nil if none was saved.
+	Load(ctx context.Context) (*EventCursor, error)
+
+	// Save replaces the saved cursor with cursor.
+	Save(ctx context.Context, cursor *EventCursor) error
+}
+
+// MemoryCursorStore is a CursorStore keeping the cursor in memory, which is
+// lost when the process exits.
+type MemoryCursorStore struct {
+	mu     sync.Mutex
+	cursor *EventCursor
+}
+
+func (s *MemoryCursorStore) Load(ctx context.Context) (*EventCursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cursor == nil {
+		return nil, nil
+	}
+	cursor := *s.cursor
+	cursor.Ids = append([]string(nil), s.cursor.Ids...)
+	return &cursor, nil
+}
+
+func (s *MemoryCursorStore) Save(ctx context.Context, cursor *EventCursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved := *cursor
+	saved.Ids = append([]string(nil), cursor.Ids...)
+	s.cursor = &saved
+	return nil
+}
+
+// FileCursorStore is a CursorStore keeping the cursor in a JSON file. It must
+// not be shared between processes.
+type FileCursorStore struct {
+	path string
+}
+
+// NewFileCursorStore returns a store kept in the file at path, which is
+// created when a cursor is first saved.
+func NewFileCursorStore(path string) *FileCursorStore {
+	return &FileCursorStore{path: path}
+}
+
+func (s *FileCursorStore) Load(ctx context.Context) (*EventCursor, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	cursor := new(EventCursor)
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+func (s *FileCursorStore) Save(ctx context.Context, cursor *EventCursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// EventTailer polls Balanced for new events and emits them in the order they
+// occurred, for environments that cannot receive callbacks:
+//
+//	tailer := balanced.NewEventTailer(client,
+//		balanced.WithCursorStore(balanced.NewFileCursorStore("events.cursor")))
+//	err := tailer.Run(ctx, func(ctx context.Context, event *balanced.Event) error {
+//		...
+//	})
+//
+// The tailer saves its cursor after every event emitted, so that a restarted
+// tailer skips no event and emits none twice, except an event that was being
+// handled when the process stopped. When there are no new events, it polls
+// less and less often, up to the maximum poll interval.
+type EventTailer struct {
+	events *EventService
+	store  CursorStore
+	start  time.Time
+
+	minInterval time.Duration
+	maxInterval time.Duration
+}
+
+// An EventTailerOption configures an EventTailer.
+type EventTailerOption func(*EventTailer)
+
+// WithCursorStore makes the tailer save its cursor in store and resume from
+// the cursor found there. Without it, the cursor is kept in memory.
+func WithCursorStore(store CursorStore) EventTailerOption {
+	return func(t *EventTailer) {
+		t.store = store
+	}
+}
+
+// WithTailStart makes the tailer emit the events that occurred at or after
+// start, when its store has no cursor. By default, it emits every event.
+func WithTailStart(start time.Time) EventTailerOption {
+	return func(t *EventTailer) {
+		t.start = start
+	}
+}
+
+// WithPollInterval makes the tailer poll every min while there are new
+// events, and back off exponentially up to max when there are none. The
+// default is every 5 seconds, backing off up to 5 minutes, which is also used
+// in place of a min or max that is not positive. A max less than min is raised
+// to min.
+func WithPollInterval(min, max time.Duration) EventTailerOption {
+	return func(t *EventTailer) {
+		t.minInterval, t.maxInterval = min, max
+	}
+}
+
+// NewEventTailer returns a tailer of the events of client's marketplace.
+func NewEventTailer(client *Client, opts ...EventTailerOption) *EventTailer {
+	t := &EventTailer{
+		events:      client.Event,
+		store:       &MemoryCursorStore{},
+		minInterval: defaultMinPollInterval,
+		maxInterval: defaultMaxPollInterval,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.minInterval <= 0 {
+		t.minInterval = defaultMinPollInterval
+	}
+	if t.maxInterval <= 0 {
+		t.maxInterval = defaultMaxPollInterval
+	}
+	if t.maxInterval < t.minInterval {
+		t.maxInterval = t.minInterval
+	}
+	return t
+}
+
+// Run polls for new events and calls fn with each of them, in the order they
+// occurred, until ctx is done or an error occurs. When fn fails, Run returns
+// its error without moving past the event, which is emitted again by the next
+// Run. Events that did not occur at a known time are skipped.
+func (t *EventTailer) Run(ctx context.Context, fn EventHandlerFunc) error {
+	cursor, err := t.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if cursor == nil {
+		cursor = &EventCursor{OccurredAt: t.start}
+	}
+	interval := t.minInterval
+	for {
+		n, err := t.poll(ctx, cursor, fn)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			interval = t.minInterval
+		}
+		if err := sleepContext(ctx, interval); err != nil {
+			return err
+		}
+		if n == 0 {
+			interval *= 2
+			if interval > t.maxInterval {
+				interval = t.maxInterval
+			}
+		}
+	}
+}
+
+// Events runs the tailer in a goroutine until ctx is done, sending the events
+// on the first channel returned. The tailer moves past an event once it was
+// received. When the tailer stops, the event channel is closed and the error
+// that stopped it is sent on the second channel.
+func (t *EventTailer) Events(ctx context.Context) (<-chan *Event, <-chan error) {
+	events := make(chan *Event)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(events)
+		errc <- t.Run(ctx, func(ctx context.Context, event *Event) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return events, errc
+}
+
+// poll emits the events past cursor, advancing it, and returns the number of
+// events emitted.
+func (t *EventTailer) poll(ctx context.Context, cursor *EventCursor, fn EventHandlerFunc) (int, error) {
+	// Listing from the oldest keeps the offsets of the pages stable while new
+	// events occur.
+	opts := &ListOptions{Limit: tailPageSize, Sort: "occurred_at,asc"}
+	if !cursor.OccurredAt.IsZero() {
+		// Times are filtered to the second; the events of the last second
+		// already emitted are skipped by the cursor.
+		opts.Filters = map[string]string{
+			"occurred_at[>=]": cursor.OccurredAt.UTC().Truncate(time.Second).Format(time.RFC3339),
+		}
+	}
+	n := 0
+	for event, err := range t.events.IterContext(ctx, opts).All() {
+		if err != nil {
+			return n, err
+		}
+		if event.OccurredAt == nil || cursor.passed(event) {
+			continue
+		}
+		if err := fn(ctx, event); err != nil {
+			return n, err
+		}
+		cursor.advance(event)
+		n++
+		if err := t.store.Save(ctx, cursor); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -1,11 +1,69 @@
 package balanced
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// Types of events, as in Event.Type.
+const (
+	EventBankAccountCreated = "bank_account.created"
+	EventBankAccountUpdated = "bank_account.updated"
+	EventBankAccountDeleted = "bank_account.deleted"
+
+	EventVerificationCreated = "bank_account_verification.created"
+	EventVerificationUpdated = "bank_account_verification.updated"
+
+	EventCardCreated = "card.created"
+	EventCardUpdated = "card.updated"
+	EventCardDeleted = "card.deleted"
+
+	EventCardHoldCreated   = "card_hold.created"
+	EventCardHoldUpdated   = "card_hold.updated"
+	EventCardHoldSucceeded = "card_hold.succeeded"
+	EventCardHoldFailed    = "card_hold.failed"
+
+	EventCreditCreated   = "credit.created"
+	EventCreditUpdated   = "credit.updated"
+	EventCreditSucceeded = "credit.succeeded"
+	EventCreditFailed    = "credit.failed"
+	EventCreditCanceled  = "credit.canceled"
+
+	EventCustomerCreated = "customer.created"
+	EventCustomerUpdated = "customer.updated"
+	EventCustomerDeleted = "customer.deleted"
+
+	EventDebitCreated   = "debit.created"
+	EventDebitUpdated   = "debit.updated"
+	EventDebitSucceeded = "debit.succeeded"
+	EventDebitFailed    = "debit.failed"
+	EventDebitCanceled  = "debit.canceled"
+
+	EventDisputeCreated = "dispute.created"
+	EventDisputeUpdated = "dispute.updated"
+
+	EventOrderCreated = "order.created"
+	EventOrderUpdated = "order.updated"
+
+	EventRefundCreated   = "refund.created"
+	EventRefundUpdated   = "refund.updated"
+	EventRefundSucceeded = "refund.succeeded"
+	EventRefundFailed    = "refund.failed"
+
+	EventReversalCreated   = "reversal.created"
+	EventReversalUpdated   = "reversal.updated"
+	EventReversalSucceeded = "reversal.succeeded"
+	EventReversalFailed    = "reversal.failed"
+)
+
+// ErrEventTypeMismatch is returned by the accessors of Event (e.g.,
+// Event.Debit) for events about another kind of resource.
+var ErrEventTypeMismatch = errors.New("balanced: event type mismatch")
+
 type EventService struct {
 	client *Client
 }
@@ -28,12 +86,80 @@ type EventEntity struct {
 	Cards         []Card         `json:"cards,omitempty"`
 	CardHolds     []CardHold     `json:"card_holds,omitempty"`
 	Debits        []Debit        `json:"debits,omitempty"`
-	Credits       []Credit       `json:"credits,omitempty"`       // Not found in docs
-	Disputes      []Dispute      `json:"disputes,omitempty"`      // Not found in docs
-	Orders        []Order        `json:"orders,omitempty"`        // Not found in docs
-	Refunds       []Refund       `json:"refunds,omitempty"`       // Not found in docs
-	Reversals     []Reversal     `json:"reversals,omitempty"`     // Not found in docs
-	Verifications []Verification `json:"verifications,omitempty"` // Not found in docs
+	Credits       []Credit       `json:"credits,omitempty"`                    // Not found in docs
+	Disputes      []Dispute      `json:"disputes,omitempty"`                   // Not found in docs
+	Orders        []Order        `json:"orders,omitempty"`                     // Not found in docs
+	Refunds       []Refund       `json:"refunds,omitempty"`                    // Not found in docs
+	Reversals     []Reversal     `json:"reversals,omitempty"`                  // Not found in docs
+	Verifications []Verification `json:"bank_account_verifications,omitempty"` // Not found in docs
+}
+
+// eventEntity returns the resource the event is about, if the event is about
+// a resource of the given kind (e.g., "debit" for "debit.succeeded").
+func eventEntity[T any](e *Event, kind string, resources func(*EventEntity) []T) (*T, error) {
+	if !strings.HasPrefix(e.Type, kind+".") {
+		return nil, fmt.Errorf("%w: event %v is a %v event, not a %v event", ErrEventTypeMismatch, e.Id, e.Type, kind)
+	}
+	if e.Entity == nil || len(resources(e.Entity)) == 0 {
+		return nil, fmt.Errorf("balanced: %v event %v has no %v", e.Type, e.Id, kind)
+	}
+	return &resources(e.Entity)[0], nil
+}
+
+// BankAccount returns the bank account a bank_account.* event is about.
+func (e *Event) BankAccount() (*BankAccount, error) {
+	return eventEntity(e, "bank_account", func(entity *EventEntity) []BankAccount { return entity.BankAccounts })
+}
+
+// Verification returns the verification a bank_account_verification.* event
+// is about.
+func (e *Event) Verification() (*Verification, error) {
+	return eventEntity(e, "bank_account_verification", func(entity *EventEntity) []Verification { return entity.Verifications })
+}
+
+// Card returns the card a card.* event is about.
+func (e *Event) Card() (*Card, error) {
+	return eventEntity(e, "card", func(entity *EventEntity) []Card { return entity.Cards })
+}
+
+// CardHold returns the card hold a card_hold.* event is about.
+func (e *Event) CardHold() (*CardHold, error) {
+	return eventEntity(e, "card_hold", func(entity *EventEntity) []CardHold { return entity.CardHolds })
+}
+
+// Credit returns the credit a credit.* event is about.
+func (e *Event) Credit() (*Credit, error) {
+	return eventEntity(e, "credit", func(entity *EventEntity) []Credit { return entity.Credits })
+}
+
+// Customer returns the customer a customer.* event is about.
+func (e *Event) Customer() (*Customer, error) {
+	return eventEntity(e, "customer", func(entity *EventEntity) []Customer { return entity.Customers })
+}
+
+// Debit returns the debit a debit.* event is about.
+func (e *Event) Debit() (*Debit, error) {
+	return eventEntity(e, "debit", func(entity *EventEntity) []Debit { return entity.Debits })
+}
+
+// Dispute returns the dispute a dispute.* event is about.
+func (e *Event) Dispute() (*Dispute, error) {
+	return eventEntity(e, "dispute", func(entity *EventEntity) []Dispute { return entity.Disputes })
+}
+
+// Order returns the order an order.* event is about.
+func (e *Event) Order() (*Order, error) {
+	return eventEntity(e, "order", func(entity *EventEntity) []Order { return entity.Orders })
+}
+
+// Refund returns the refund a refund.* event is about.
+func (e *Event) Refund() (*Refund, error) {
+	return eventEntity(e, "refund", func(entity *EventEntity) []Refund { return entity.Refunds })
+}
+
+// Reversal returns the reversal a reversal.* event is about.
+func (e *Event) Reversal() (*Reversal, error) {
+	return eventEntity(e, "reversal", func(entity *EventEntity) []Reversal { return entity.Reversals })
 }
 
 type CallbackStatuses struct {
@@ -44,13 +170,13 @@ type CallbackStatuses struct {
 }
 
 type eventResponse struct {
-	Events []Event                `json:"events"`
-	Links  *eventResponseLinks    `json:"links"`
-	Meta   map[string]interface{} `json:"meta"`
+	Events []Event             `json:"events"`
+	Links  *eventResponseLinks `json:"links"`
+	Meta   *PaginationParams   `json:"meta"`
 }
 
 type eventResponseLinks struct {
-	Callbacks string `json:"events.callbacks"` // "/events/{events.self}/callbacks"
+	Callbacks string `json:"events.callbacks" template:"/events/{events.self}/callbacks"`
 }
 
 type EventPage struct {
@@ -58,10 +184,16 @@ type EventPage struct {
 	*PaginationParams
 }
 
+type EventIterator = Iterator[Event]
+
 func (s *EventService) Fetch(eventId string) (*Event, *http.Response, error) {
+	return s.FetchContext(context.Background(), eventId)
+}
+
+func (s *EventService) FetchContext(ctx context.Context, eventId string) (*Event, *http.Response, error) {
 	path := fmt.Sprintf("/events/%v", eventId)
 	eventResponse := new(eventResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, eventResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, eventResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -69,15 +201,74 @@ func (s *EventService) Fetch(eventId string) (*Event, *http.Response, error) {
 }
 
 func (s *EventService) List(args ...interface{}) (*EventPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *EventService) ListContext(ctx context.Context, args ...interface{}) (*EventPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/events", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *EventService) ListWith(opts *ListOptions) (*EventPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *EventService) ListWithContext(ctx context.Context, opts *ListOptions) (*EventPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *EventService) list(ctx context.Context, path string, query map[string]interface{}) (*EventPage, *http.Response, error) {
 	eventResponse := new(eventResponse)
-	httpResponse, err := s.client.GET("/events", query, nil, eventResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, eventResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &EventPage{
 		Events:           eventResponse.Events,
-		PaginationParams: NewPaginationParams(eventResponse.Meta),
+		PaginationParams: eventResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
+
+// FetchPage fetches the page of events at href, such as the Next or Previous
+// link of another page.
+func (s *EventService) FetchPage(href string) (*EventPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *EventService) FetchPageContext(ctx context.Context, href string) (*EventPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all events, fetching pages as needed. args are
+// the same as for List.
+func (s *EventService) Iter(args ...interface{}) *EventIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *EventService) IterContext(ctx context.Context, args ...interface{}) *EventIterator {
+	return newIterator(ctx, "/events", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *EventService) IterWith(opts *ListOptions) *EventIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *EventService) IterWithContext(ctx context.Context, opts *ListOptions) *EventIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *EventService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Event, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Events, page.PaginationParams, nil
+}
@@ -0,0 +1,105 @@
+package balanced
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// A FundingInstrument is a source or destination of funds: a *Card or a
+// *BankAccount.
+type FundingInstrument interface {
+	// InstrumentId returns the id of the card or bank account.
+	InstrumentId() string
+	fundingInstrument()
+}
+
+func (c *Card) InstrumentId() string { return c.Id }
+func (c *Card) fundingInstrument()   {}
+
+func (a *BankAccount) InstrumentId() string { return a.Id }
+func (a *BankAccount) fundingInstrument()   {}
+
+// FundingInstrumentService moves money with cards and bank accounts alike.
+type FundingInstrumentService struct {
+	client *Client
+}
+
+// Debit takes money from the funding instrument: it charges a card or debits
+// a bank account.
+func (s *FundingInstrumentService) Debit(instrument FundingInstrument, debit *Debit) (*Debit, *http.Response, error) {
+	return s.DebitContext(context.Background(), instrument, debit)
+}
+
+func (s *FundingInstrumentService) DebitContext(ctx context.Context, instrument FundingInstrument, debit *Debit) (*Debit, *http.Response, error) {
+	switch instrument := instrument.(type) {
+	case *Card:
+		return s.client.Card.ChargeContext(ctx, instrument.Id, debit)
+	case *BankAccount:
+		return s.client.BankAccount.DebitContext(ctx, instrument.Id, debit)
+	}
+	return nil, nil, fmt.Errorf("balanced: cannot debit %T", instrument)
+}
+
+// Credit pays money out to the funding instrument.
+func (s *FundingInstrumentService) Credit(instrument FundingInstrument, credit *Credit) (*Credit, *http.Response, error) {
+	return s.CreditContext(context.Background(), instrument, credit)
+}
+
+func (s *FundingInstrumentService) CreditContext(ctx context.Context, instrument FundingInstrument, credit *Credit) (*Credit, *http.Response, error) {
+	switch instrument := instrument.(type) {
+	case *Card:
+		return s.client.Card.CreditContext(ctx, instrument.Id, credit)
+	case *BankAccount:
+		return s.client.BankAccount.CreditContext(ctx, instrument.Id, credit)
+	}
+	return nil, nil, fmt.Errorf("balanced: cannot credit %T", instrument)
+}
+
+// AssociateWithCustomer associates the funding instrument with a customer and
+// returns the updated card or bank account.
+func (s *FundingInstrumentService) AssociateWithCustomer(instrument FundingInstrument, customerId string) (FundingInstrument, *http.Response, error) {
+	return s.AssociateWithCustomerContext(context.Background(), instrument, customerId)
+}
+
+func (s *FundingInstrumentService) AssociateWithCustomerContext(ctx context.Context, instrument FundingInstrument, customerId string) (FundingInstrument, *http.Response, error) {
+	switch instrument := instrument.(type) {
+	case *Card:
+		card, httpResponse, err := s.client.Card.AssociateWithCustomerContext(ctx, instrument.Id, customerId)
+		if err != nil {
+			return nil, httpResponse, err
+		}
+		return card, httpResponse, nil
+	case *BankAccount:
+		account, httpResponse, err := s.client.BankAccount.AssociateWithCustomerContext(ctx, instrument.Id, customerId)
+		if err != nil {
+			return nil, httpResponse, err
+		}
+		return account, httpResponse, nil
+	}
+	return nil, nil, fmt.Errorf("balanced: cannot associate %T with a customer", instrument)
+}
+
+// Fetch fetches the card or bank account at href, e.g. "/cards/CC123" or
+// "/resources/BA123".
+func (s *FundingInstrumentService) Fetch(href string) (FundingInstrument, *http.Response, error) {
+	return s.FetchContext(context.Background(), href)
+}
+
+func (s *FundingInstrumentService) FetchContext(ctx context.Context, href string) (FundingInstrument, *http.Response, error) {
+	resource, httpResponse, err := s.client.FetchHrefContext(ctx, href)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return toFundingInstrument(resource, httpResponse, href)
+}
+
+// toFundingInstrument returns resource as a FundingInstrument, or an error if
+// the resource at href is neither a card nor a bank account.
+func toFundingInstrument(resource interface{}, httpResponse *http.Response, href string) (FundingInstrument, *http.Response, error) {
+	instrument, ok := resource.(FundingInstrument)
+	if !ok {
+		return nil, httpResponse, fmt.Errorf("balanced: %v is not a funding instrument but a %T", href, resource)
+	}
+	return instrument, httpResponse, nil
+}
@@ -0,0 +1,135 @@
+package balanced
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying key. Money-moving calls
+// made with the returned context (e.g., CardService.ChargeContext) send key as
+// their idempotency key instead of generating one. Repeating a call with the
+// same key replays its response from Client.IdempotencyCache if the call
+// succeeded in this process; otherwise, e.g. after a timeout or a restart,
+// money is moved only once if the API honours the key.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key carried by ctx, if
+// any.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// NewIdempotencyKey returns a random key suitable for WithIdempotencyKey.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// IdempotencyCache remembers the successful responses to recent requests
+// whose idempotency key was chosen with WithIdempotencyKey. When a request is
+// made again with the same key, Client.Do replays the remembered response
+// instead of sending it. The cache is kept in memory and lost when the
+// process exits.
+type IdempotencyCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	records map[string]*idempotencyRecord
+	order   []string // keys of records, oldest first
+}
+
+type idempotencyRecord struct {
+	status    string
+	code      int
+	header    http.Header
+	body      []byte
+	expiresAt time.Time
+}
+
+// NewIdempotencyCache returns a cache holding up to maxEntries responses for
+// at most ttl each.
+func NewIdempotencyCache(ttl time.Duration, maxEntries int) *IdempotencyCache {
+	return &IdempotencyCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		records:    make(map[string]*idempotencyRecord),
+	}
+}
+
+func idempotencyCacheKey(req *http.Request) string {
+	return req.Method + " " + req.URL.Path + " " + req.Header.Get(IdempotencyKeyHeader)
+}
+
+// replay returns the remembered response to req, if there is one.
+func (c *IdempotencyCache) replay(req *http.Request) (*http.Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	record, ok := c.records[idempotencyCacheKey(req)]
+	if !ok || time.Now().After(record.expiresAt) {
+		return nil, false
+	}
+	return &http.Response{
+		Status:     record.status,
+		StatusCode: record.code,
+		Header:     record.header.Clone(),
+		Body:       ioutil.NopCloser(bytes.NewReader(record.body)),
+		Request:    req,
+	}, true
+}
+
+// remember records body as the response to req.
+func (c *IdempotencyCache) remember(req *http.Request, res *http.Response, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := idempotencyCacheKey(req)
+	if _, ok := c.records[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.records[key] = &idempotencyRecord{
+		status:    res.Status,
+		code:      res.StatusCode,
+		header:    res.Header.Clone(),
+		body:      body,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	now := time.Now()
+	for len(c.order) > 0 {
+		oldest := c.records[c.order[0]]
+		if len(c.order) <= c.maxEntries && now.Before(oldest.expiresAt) {
+			break
+		}
+		delete(c.records, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// idempotentPOSTContext is like POSTContext, but sends the idempotency key
+// carried by ctx, or a freshly generated one. The same key is reused when the
+// request is retried.
+func (c *Client) idempotentPOSTContext(ctx context.Context, urlPath string, query map[string]interface{}, reqBody interface{}, resObj interface{}) (*http.Response, error) {
+	key, ok := IdempotencyKeyFromContext(ctx)
+	if !ok {
+		key = NewIdempotencyKey()
+	}
+	req, err := c.NewRequestContext(ctx, "POST", urlPath, query, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return c.Do(req, resObj)
+}
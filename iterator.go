@@ -0,0 +1,97 @@
+package balanced
+
+import (
+	"context"
+	"iter"
+)
+
+// fetchPageFunc fetches the page of a list found at path.
+type fetchPageFunc[T any] func(ctx context.Context, path string, query map[string]interface{}) ([]T, *PaginationParams, error)
+
+// Iterator walks every item of a paginated list, fetching the following pages
+// by their next links as it goes. Either call Next until it returns false and
+// then check Err:
+//
+//	it := client.Debit.Iter()
+//	for it.Next() {
+//		debit := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// or range over All, which yields a non-nil error at most once, last:
+//
+//	for debit, err := range client.Debit.Iter().All() {
+//		...
+//	}
+//
+// Stopping early (or breaking out of the loop) fetches no further pages.
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch fetchPageFunc[T]
+
+	path  string // path of the next page to fetch; empty when there is none
+	query map[string]interface{}
+
+	items []T
+	index int
+	err   error
+}
+
+// newIterator returns an iterator over the list at path, filtered by args as
+// for List. Invalid args are reported by Err.
+func newIterator[T any](ctx context.Context, path string, args []interface{}, fetch fetchPageFunc[T]) *Iterator[T] {
+	query, err := paginatedArgsToQuery(args)
+	return &Iterator[T]{ctx: ctx, fetch: fetch, path: path, query: query, index: -1, err: err}
+}
+
+// Next advances to the next item, fetching the next page if needed. It
+// returns false when there are no more items or a fetch failed.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	for it.index >= len(it.items) {
+		if it.path == "" {
+			return false
+		}
+		items, params, err := it.fetch(it.ctx, it.path, it.query)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.items, it.index = items, 0
+		// The next link already carries the query of the list.
+		it.path, it.query = params.Next, nil
+	}
+	return true
+}
+
+// Value returns the current item. It is only valid after Next returned true.
+func (it *Iterator[T]) Value() *T {
+	return &it.items[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All returns a sequence of the remaining items, for use with range. If
+// fetching a page fails, the error is yielded with a nil item and the
+// sequence ends.
+func (it *Iterator[T]) All() iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for it.Next() {
+			if !yield(it.Value(), nil) {
+				return
+			}
+		}
+		if it.err != nil {
+			yield(nil, it.err)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package balanced
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// responseLinkTypes are the links of the responses of the API. Each field is
+// tagged with the name of a link and the href template the API gives for it,
+// in which a placeholder such as {debits.source} stands for the "source" link
+// of the debit, and {debits.self} or {debits.id} for the id of the debit
+// itself.
+var responseLinkTypes = []interface{}{
+	apiKeyResponseLinks{}, bankAccountResponseLinks{}, callbackResponseLinks{},
+	cardHoldResponseLinks{}, cardResponseLinks{}, creditResponseLinks{},
+	customerResponseLinks{}, debitResponseLinks{}, disputeResponseLinks{},
+	eventResponseLinks{}, marketplaceResponseLinks{}, orderResponseLinks{},
+	refundResponseLinks{}, reversalResponseLinks{}, verificationResponseLinks{},
+}
+
+// linkTemplates holds the href templates of responseLinkTypes by link name.
+var linkTemplates = tagTemplates(responseLinkTypes)
+
+// tagTemplates maps the json tag of each field of linkTypes to its template
+// tag.
+func tagTemplates(linkTypes []interface{}) map[string]string {
+	templates := make(map[string]string)
+	for _, links := range linkTypes {
+		t := reflect.TypeOf(links)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			templates[field.Tag.Get("json")] = field.Tag.Get("template")
+		}
+	}
+	return templates
+}
+
+var linkPlaceholder = regexp.MustCompile(`\{[^}]*\}`)
+
+// ExpandLink expands the placeholders of an href template against resource, a
+// pointer to a resource such as a *Debit. {debits.self} and {debits.id} expand
+// to the Id of the resource, and any other {x.name} to the field of its Links
+// tagged "name", e.g.:
+//
+//	href, err := balanced.ExpandLink("/resources/{debits.source}", debit)
+//
+// It returns an error if the resource lacks any of the values.
+func ExpandLink(template string, resource interface{}) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(resource))
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("balanced: cannot expand links of %T", resource)
+	}
+	var err error
+	href := linkPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		value := linkValue(v, name)
+		if value == "" && err == nil {
+			err = fmt.Errorf("balanced: %T has no value for %v", resource, placeholder)
+		}
+		return url.PathEscape(value)
+	})
+	if err != nil {
+		return "", err
+	}
+	return href, nil
+}
+
+// linkValue returns the value named name of the resource v for a link
+// placeholder, or "" if it has none.
+func linkValue(v reflect.Value, name string) string {
+	if name == "self" || name == "id" {
+		if id := v.FieldByName("Id"); id.Kind() == reflect.String {
+			return id.String()
+		}
+		return ""
+	}
+	links := reflect.Indirect(v.FieldByName("Links"))
+	if links.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < links.NumField(); i++ {
+		tag := strings.Split(links.Type().Field(i).Tag.Get("json"), ",")[0]
+		if tag == name && links.Field(i).Kind() == reflect.String {
+			return links.Field(i).String()
+		}
+	}
+	return ""
+}
+
+// LinkHref returns the href of the link named rel (e.g., "debits.source") of
+// resource.
+func LinkHref(rel string, resource interface{}) (string, error) {
+	template, ok := linkTemplates[rel]
+	if !ok {
+		return "", fmt.Errorf("balanced: unknown link %q", rel)
+	}
+	return ExpandLink(template, resource)
+}
+
+// FollowLink fetches the target of the link named rel (e.g., "debits.source")
+// of resource. A link to a single resource yields a pointer to it, such as a
+// *Card or *BankAccount for "debits.source"; a link to a list yields its first
+// page, such as a *RefundPage for "debits.refunds".
+func (c *Client) FollowLink(rel string, resource interface{}) (interface{}, *http.Response, error) {
+	return c.FollowLinkContext(context.Background(), rel, resource)
+}
+
+func (c *Client) FollowLinkContext(ctx context.Context, rel string, resource interface{}) (interface{}, *http.Response, error) {
+	href, err := LinkHref(rel, resource)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.FetchHrefContext(ctx, href)
+}
+
+// resourceDecoder decodes the resources held by a response: the first one, or
+// if many, the page of them.
+type resourceDecoder func(raw json.RawMessage, meta *PaginationParams, many bool) (interface{}, error)
+
+func decodeResources[T any](newPage func([]T, *PaginationParams) interface{}) resourceDecoder {
+	return func(raw json.RawMessage, meta *PaginationParams, many bool) (interface{}, error) {
+		var items []T
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+		if many {
+			return newPage(items, meta.orEmpty()), nil
+		}
+		if len(items) == 0 {
+			return nil, ErrNotFound
+		}
+		return &items[0], nil
+	}
+}
+
+// resourceDecoders maps the key under which a response holds its resources to
+// their decoder.
+var resourceDecoders = map[string]resourceDecoder{
+	"api_keys": decodeResources(func(items []ApiKey, params *PaginationParams) interface{} {
+		return &ApiKeyPage{ApiKeys: items, PaginationParams: params}
+	}),
+	"bank_account_verifications": decodeResources(func(items []Verification, params *PaginationParams) interface{} {
+		return &VerificationPage{Verifications: items, PaginationParams: params}
+	}),
+	"bank_accounts": decodeResources(func(items []BankAccount, params *PaginationParams) interface{} {
+		return &BankAccountPage{BankAccounts: items, PaginationParams: params}
+	}),
+	"callbacks": decodeResources(func(items []Callback, params *PaginationParams) interface{} {
+		return &CallbackPage{Callbacks: items, PaginationParams: params}
+	}),
+	"card_holds": decodeResources(func(items []CardHold, params *PaginationParams) interface{} {
+		return &CardHoldPage{CardHolds: items, PaginationParams: params}
+	}),
+	"cards": decodeResources(func(items []Card, params *PaginationParams) interface{} {
+		return &CardPage{Cards: items, PaginationParams: params}
+	}),
+	"credits": decodeResources(func(items []Credit, params *PaginationParams) interface{} {
+		return &CreditPage{Credits: items, PaginationParams: params}
+	}),
+	"customers": decodeResources(func(items []Customer, params *PaginationParams) interface{} {
+		return &CustomerPage{Customers: items, PaginationParams: params}
+	}),
+	"debits": decodeResources(func(items []Debit, params *PaginationParams) interface{} {
+		return &DebitPage{Debits: items, PaginationParams: params}
+	}),
+	"disputes": decodeResources(func(items []Dispute, params *PaginationParams) interface{} {
+		return &DisputePage{Disputes: items, PaginationParams: params}
+	}),
+	"events": decodeResources(func(items []Event, params *PaginationParams) interface{} {
+		return &EventPage{Events: items, PaginationParams: params}
+	}),
+	"marketplaces": decodeResources(func(items []Marketplace, params *PaginationParams) interface{} {
+		return &MarketplacePage{Marketplaces: items, PaginationParams: params}
+	}),
+	"orders": decodeResources(func(items []Order, params *PaginationParams) interface{} {
+		return &OrderPage{Orders: items, PaginationParams: params}
+	}),
+	"refunds": decodeResources(func(items []Refund, params *PaginationParams) interface{} {
+		return &RefundPage{Refunds: items, PaginationParams: params}
+	}),
+	"reversals": decodeResources(func(items []Reversal, params *PaginationParams) interface{} {
+		return &ReversalPage{Reversals: items, PaginationParams: params}
+	}),
+}
+
+// hrefKeys maps the names of lists in hrefs to the key under which responses
+// hold their resources, where the two differ.
+var hrefKeys = map[string]string{
+	"buyers":        "customers",
+	"verifications": "bank_account_verifications",
+}
+
+// hrefKind returns the key under which the response to href holds its
+// resources, as told by the path of href, and whether href is the href of a
+// list rather than of a single resource. Paths alternate between names of
+// lists and ids: "/cards" and "/customers/CU123/cards" are lists, while
+// "/cards/CC123" is a single card.
+func (c *Client) hrefKind(href string) (key string, many bool, err error) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", false, err
+	}
+	path := u.Path
+	if u.IsAbs() {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(c.baseURL.Path, "/"))
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	many = len(segments)%2 == 1
+	key = segments[len(segments)-1]
+	if !many {
+		key = segments[len(segments)-2]
+	}
+	if k, ok := hrefKeys[key]; ok {
+		key = k
+	}
+	return key, many, nil
+}
+
+// FetchHref fetches the resource at href, e.g. "/debits/WD123" or a full URL,
+// decoded to its type. The href of a single resource yields a pointer to it
+// (e.g., a *Debit), and the href of a list yields its first page (e.g., a
+// *DebitPage, or a *TransactionPage for a list of transactions). The kind of
+// resource is told from the path of href, or else from the response, as for
+// "/resources/CC123".
+func (c *Client) FetchHref(href string) (interface{}, *http.Response, error) {
+	return c.FetchHrefContext(context.Background(), href)
+}
+
+func (c *Client) FetchHrefContext(ctx context.Context, href string) (interface{}, *http.Response, error) {
+	key, many, err := c.hrefKind(href)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var raw json.RawMessage
+	httpResponse, err := c.GETContext(ctx, href, nil, nil, &raw)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+
+	if key == "transactions" && many {
+		transactionResponse := new(transactionResponse)
+		if err := json.Unmarshal(raw, transactionResponse); err != nil {
+			return nil, httpResponse, err
+		}
+		return &TransactionPage{
+			Transactions:     transactionResponse.transactions(href, nil),
+			PaginationParams: transactionResponse.Meta.orEmpty(),
+		}, httpResponse, nil
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, httpResponse, err
+	}
+	if _, ok := body[key]; !ok || resourceDecoders[key] == nil {
+		// The path does not tell; go by the one kind of resource in the response.
+		key = ""
+		for k := range body {
+			if resourceDecoders[k] == nil {
+				continue
+			}
+			if key != "" {
+				key = ""
+				break
+			}
+			key = k
+		}
+		if key == "" {
+			return nil, httpResponse, fmt.Errorf("balanced: cannot tell the kind of resource at %v", href)
+		}
+	}
+
+	var meta *PaginationParams
+	if rawMeta, ok := body["meta"]; ok {
+		if err := json.Unmarshal(rawMeta, &meta); err != nil {
+			return nil, httpResponse, err
+		}
+	}
+	resource, err := resourceDecoders[key](body[key], meta, many)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return resource, httpResponse, nil
+}
@@ -1,6 +1,8 @@
 package balanced
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"time"
 )
@@ -32,28 +34,187 @@ type MarketplaceLinks struct {
 type marketplaceResponse struct {
 	Marketplaces []Marketplace             `json:"marketplaces"`
 	Links        *marketplaceResponseLinks `json:"links"`
+	Meta         *PaginationParams         `json:"meta"`
+}
+
+type MarketplacePage struct {
+	Marketplaces []Marketplace
+	*PaginationParams
+}
+
+type MarketplaceIterator = Iterator[Marketplace]
+
+// MarketplaceOwner is the customer owning a marketplace along with its funding
+// instruments. Money paid out of escrow to the marketplace goes to these.
+type MarketplaceOwner struct {
+	Customer     *Customer
+	Cards        []Card
+	BankAccounts []BankAccount
 }
 
 type marketplaceResponseLinks struct {
-	Reversals     string `json:"marketplaces.reversals"`
-	Cards         string `json:"marketplaces.cards"`
-	Refunds       string `json:"marketplaces.refunds"`
-	BankAccounts  string `json:"marketplaces.bank_accounts"`
-	Debits        string `json:"marketplaces.debits"`
-	Customers     string `json:"marketplaces.customers"`
-	Credits       string `json:"marketplaces.credits"`
-	CardHolds     string `json:"marketplaces.card_holds"`
-	OwnerCustomer string `json:"marketplaces.owner_customer"`
-	Transactions  string `json:"marketplaces.transactions"`
-	Callbacks     string `json:"marketplaces.callbacks"`
-	Events        string `json:"marketplaces.events"`
+	Reversals     string `json:"marketplaces.reversals" template:"/reversals"`
+	Cards         string `json:"marketplaces.cards" template:"/cards"`
+	Refunds       string `json:"marketplaces.refunds" template:"/refunds"`
+	BankAccounts  string `json:"marketplaces.bank_accounts" template:"/bank_accounts"`
+	Debits        string `json:"marketplaces.debits" template:"/debits"`
+	Customers     string `json:"marketplaces.customers" template:"/customers"`
+	Credits       string `json:"marketplaces.credits" template:"/credits"`
+	CardHolds     string `json:"marketplaces.card_holds" template:"/card_holds"`
+	OwnerCustomer string `json:"marketplaces.owner_customer" template:"/customers/{marketplaces.owner_customer}"`
+	Transactions  string `json:"marketplaces.transactions" template:"/transactions"`
+	Callbacks     string `json:"marketplaces.callbacks" template:"/callbacks"`
+	Events        string `json:"marketplaces.events" template:"/events"`
 }
 
 func (s *MarketplaceService) Create() (*Marketplace, *http.Response, error) {
+	return s.CreateContext(context.Background())
+}
+
+func (s *MarketplaceService) CreateContext(ctx context.Context) (*Marketplace, *http.Response, error) {
 	marketplaceResponse := new(marketplaceResponse)
-	httpResponse, err := s.client.POST("/marketplaces", nil, nil, marketplaceResponse)
+	httpResponse, err := s.client.POSTContext(ctx, "/marketplaces", nil, nil, marketplaceResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &marketplaceResponse.Marketplaces[0], httpResponse, err
 }
+
+func (s *MarketplaceService) Fetch(marketplaceId string) (*Marketplace, *http.Response, error) {
+	return s.FetchContext(context.Background(), marketplaceId)
+}
+
+func (s *MarketplaceService) FetchContext(ctx context.Context, marketplaceId string) (*Marketplace, *http.Response, error) {
+	path := fmt.Sprintf("/marketplaces/%v", marketplaceId)
+	marketplaceResponse := new(marketplaceResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, marketplaceResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &marketplaceResponse.Marketplaces[0], httpResponse, nil
+}
+
+// List lists the marketplaces the client's API key has access to, which is
+// usually just one.
+func (s *MarketplaceService) List(args ...interface{}) (*MarketplacePage, *http.Response, error) {
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *MarketplaceService) ListContext(ctx context.Context, args ...interface{}) (*MarketplacePage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/marketplaces", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *MarketplaceService) ListWith(opts *ListOptions) (*MarketplacePage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *MarketplaceService) ListWithContext(ctx context.Context, opts *ListOptions) (*MarketplacePage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *MarketplaceService) list(ctx context.Context, path string, query map[string]interface{}) (*MarketplacePage, *http.Response, error) {
+	marketplaceResponse := new(marketplaceResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, marketplaceResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &MarketplacePage{
+		Marketplaces:     marketplaceResponse.Marketplaces,
+		PaginationParams: marketplaceResponse.Meta.orEmpty(),
+	}, httpResponse, nil
+}
+
+// FetchPage fetches the page of marketplaces at href, such as the Next or
+// Previous link of another page.
+func (s *MarketplaceService) FetchPage(href string) (*MarketplacePage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *MarketplaceService) FetchPageContext(ctx context.Context, href string) (*MarketplacePage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all marketplaces, fetching pages as needed.
+// args are the same as for List.
+func (s *MarketplaceService) Iter(args ...interface{}) *MarketplaceIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *MarketplaceService) IterContext(ctx context.Context, args ...interface{}) *MarketplaceIterator {
+	return newIterator(ctx, "/marketplaces", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *MarketplaceService) IterWith(opts *ListOptions) *MarketplaceIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *MarketplaceService) IterWithContext(ctx context.Context, opts *ListOptions) *MarketplaceIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *MarketplaceService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Marketplace, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Marketplaces, page.PaginationParams, nil
+}
+
+// Update updates a marketplace, e.g. its "support_email_address" or
+// "domain_url".
+func (s *MarketplaceService) Update(marketplaceId string, params map[string]interface{}) (*Marketplace, *http.Response, error) {
+	return s.UpdateContext(context.Background(), marketplaceId, params)
+}
+
+func (s *MarketplaceService) UpdateContext(ctx context.Context, marketplaceId string, params map[string]interface{}) (*Marketplace, *http.Response, error) {
+	path := fmt.Sprintf("/marketplaces/%v", marketplaceId)
+	marketplaceResponse := new(marketplaceResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, marketplaceResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &marketplaceResponse.Marketplaces[0], httpResponse, nil
+}
+
+// FetchOwner fetches the customer owning the marketplace, and all its cards
+// and bank accounts.
+func (s *MarketplaceService) FetchOwner(marketplace *Marketplace) (*MarketplaceOwner, error) {
+	return s.FetchOwnerContext(context.Background(), marketplace)
+}
+
+func (s *MarketplaceService) FetchOwnerContext(ctx context.Context, marketplace *Marketplace) (*MarketplaceOwner, error) {
+	if marketplace.Links == nil || marketplace.Links.Owner == "" {
+		return nil, fmt.Errorf("balanced: marketplace %v has no owner customer link", marketplace.Id)
+	}
+	customer, _, err := s.client.Customer.FetchContext(ctx, marketplace.Links.Owner)
+	if err != nil {
+		return nil, err
+	}
+	owner := &MarketplaceOwner{Customer: customer}
+
+	cards := s.client.Customer.IterCardsContext(ctx, customer.Id)
+	for cards.Next() {
+		owner.Cards = append(owner.Cards, *cards.Value())
+	}
+	if err := cards.Err(); err != nil {
+		return nil, err
+	}
+
+	accounts := s.client.Customer.IterBankAccountsContext(ctx, customer.Id)
+	for accounts.Next() {
+		owner.BankAccounts = append(owner.BankAccounts, *accounts.Value())
+	}
+	if err := accounts.Err(); err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
@@ -0,0 +1,227 @@
+package balanced
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of amounts that do not state one.
+const DefaultCurrency = "USD"
+
+// ErrCurrencyMismatch is returned when combining or comparing amounts of
+// money in different currencies.
+var ErrCurrencyMismatch = errors.New("balanced: currency mismatch")
+
+// Money is an amount of money in cents (or the smallest unit of its currency,
+// e.g. yens) along with the ISO 4217 code of its currency, e.g. "USD". An
+// empty Currency stands for DefaultCurrency.
+//
+// Resources keep their amounts as int cents, as in the API, and return them as
+// Money from their Money methods (e.g., Debit.Money). In JSON, Money is its
+// cents alone, like the amounts of the API, whose currency is a field of its
+// own.
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+// USD returns an amount of US dollars in cents.
+func USD(cents int64) Money {
+	return Money{Cents: cents, Currency: "USD"}
+}
+
+func newMoney(cents int, currency string) Money {
+	return Money{Cents: int64(cents), Currency: currency}
+}
+
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return strings.ToUpper(m.Currency)
+}
+
+// MarshalJSON writes m as its number of cents.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Cents)
+}
+
+// UnmarshalJSON reads a number of cents into m, keeping its currency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &m.Cents)
+}
+
+// minorUnits maps the ISO 4217 codes of currencies to their number of minor
+// units, where it is not 2.
+var minorUnits = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+}
+
+// decimals returns the number of digits of the minor unit of currency.
+func decimals(currency string) int {
+	if n, ok := minorUnits[currency]; ok {
+		return n
+	}
+	return 2
+}
+
+func (m Money) checkCurrency(o Money) error {
+	if m.currency() != o.currency() {
+		return fmt.Errorf("%w: %v and %v", ErrCurrencyMismatch, m.currency(), o.currency())
+	}
+	return nil
+}
+
+// Add returns m + o. It fails with ErrCurrencyMismatch if they are in
+// different currencies.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Cents: m.Cents + o.Cents, Currency: m.currency()}, nil
+}
+
+// Sub returns m - o. It fails with ErrCurrencyMismatch if they are in
+// different currencies.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Cents: m.Cents - o.Cents, Currency: m.currency()}, nil
+}
+
+// Cmp returns -1, 0 or +1 as m is less than, equal to or greater than o. It
+// fails with ErrCurrencyMismatch if they are in different currencies.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Cents < o.Cents:
+		return -1, nil
+	case m.Cents > o.Cents:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// String formats m with a thousands separator and the minor units of its
+// currency, e.g. "$2,500.00" for US dollars, "2,500.00 EUR" for euros and
+// "500 JPY" for yens.
+func (m Money) String() string {
+	units := m.Cents
+	sign := ""
+	if units < 0 {
+		sign, units = "-", -units
+	}
+	n := decimals(m.currency())
+	scale := pow10(n)
+	major := strconv.FormatInt(units/scale, 10)
+	for i := len(major) - 3; i > 0; i -= 3 {
+		major = major[:i] + "," + major[i:]
+	}
+	amount := major
+	if n > 0 {
+		amount = fmt.Sprintf("%v.%0*d", major, n, units%scale)
+	}
+	if m.currency() == "USD" {
+		return sign + "$" + amount
+	}
+	return sign + amount + " " + m.currency()
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+var moneyPattern = regexp.MustCompile(`^(-)?(\$)?(\d{1,3}(?:,\d{3})+|\d+)(?:\.(\d{1,3}))?(?: ([A-Za-z]{3}))?$`)
+
+// ParseMoney parses amounts as formatted by Money.String, e.g. "$2,500.00",
+// "-$0.50", "2,500.00 EUR" or "500 JPY". The thousands separator, the minor
+// units and the dollar sign may be left out, as in "2500", which is in
+// DefaultCurrency. It fails if there are more minor digits than the currency
+// has.
+func ParseMoney(s string) (Money, error) {
+	match := moneyPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return Money{}, fmt.Errorf("balanced: invalid amount of money %q", s)
+	}
+	sign, dollar, major, minor, currency := match[1], match[2], match[3], match[4], strings.ToUpper(match[5])
+	if dollar != "" && currency != "" && currency != "USD" {
+		return Money{}, fmt.Errorf("balanced: invalid amount of money %q", s)
+	}
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	n := decimals(currency)
+	if len(minor) > n {
+		return Money{}, fmt.Errorf("balanced: invalid amount of money %q: %v has %d decimals", s, currency, n)
+	}
+	outOfRange := fmt.Errorf("balanced: invalid amount of money %q: out of range", s)
+	units, err := strconv.ParseInt(strings.Replace(major, ",", "", -1), 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return Money{}, outOfRange
+	}
+	if err != nil {
+		return Money{}, fmt.Errorf("balanced: invalid amount of money %q", s)
+	}
+	scale := pow10(n)
+	if units > math.MaxInt64/scale {
+		return Money{}, outOfRange
+	}
+	total := units * scale
+	if minor != "" {
+		m, _ := strconv.ParseInt(minor, 10, 64)
+		fraction := m * pow10(n-len(minor))
+		if total > math.MaxInt64-fraction {
+			return Money{}, outOfRange
+		}
+		total += fraction
+	}
+	if sign != "" {
+		total = -total
+	}
+	return Money{Cents: total, Currency: currency}, nil
+}
+
+// Money returns the amount of the debit.
+func (d *Debit) Money() Money { return newMoney(d.Amount, d.Currency) }
+
+// Money returns the amount of the credit.
+func (c *Credit) Money() Money { return newMoney(c.Amount, c.Currency) }
+
+// Money returns the amount of the refund.
+func (r *Refund) Money() Money { return newMoney(r.Amount, r.Currency) }
+
+// Money returns the amount of the reversal.
+func (r *Reversal) Money() Money { return newMoney(r.Amount, "") }
+
+// Money returns the amount of the card hold.
+func (h *CardHold) Money() Money { return newMoney(h.Amount, h.Currency) }
+
+// Money returns the amount of the disputed debit.
+func (d *Dispute) Money() Money { return newMoney(d.Amount, d.Currency) }
+
+// Money returns the amount debited into the order.
+func (o *Order) Money() Money { return newMoney(o.Amount, o.Currency) }
+
+// EscrowedMoney returns the amount held in escrow by the order.
+func (o *Order) EscrowedMoney() Money { return newMoney(o.AmountEscrowed, o.Currency) }
+
+// EscrowMoney returns the amount held in escrow by the marketplace.
+func (m *Marketplace) EscrowMoney() Money { return newMoney(m.InEscrow, "") }
+
+// UnsettledFeesMoney returns the fees the marketplace has yet to settle.
+func (m *Marketplace) UnsettledFeesMoney() Money { return newMoney(m.UnsettledFees, "") }
@@ -0,0 +1,102 @@
+package balanced
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// A ClientOption configures a Client. Options are passed to NewClient and
+// applied in order.
+type ClientOption func(*Client)
+
+// WithBaseUrl makes the client send its requests to baseUrl instead of the
+// Balanced API, e.g. to reach a local stand-in or go through a proxy. A path
+// in baseUrl is kept as a prefix of every request path. If baseUrl cannot be
+// parsed, every request made by the client fails with the parse error.
+func WithBaseUrl(baseUrl string) ClientOption {
+	return func(c *Client) {
+		u, err := url.Parse(baseUrl)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.baseURL = u
+	}
+}
+
+// WithApiRevision sets the API revision requested in the Accept header of
+// every request (e.g., "1.1").
+func WithApiRevision(revision string) ClientOption {
+	return func(c *Client) {
+		c.apiRevision = revision
+	}
+}
+
+// WithUserAgent appends suffix (e.g., "myapp/2.0") to the User-Agent header
+// sent with every request.
+func WithUserAgent(suffix string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = defaultUserAgent + " " + suffix
+	}
+}
+
+// WithHeader adds a header sent with every request. The Accept,
+// Content-Type, User-Agent and Authorization headers, which the client sets
+// itself, are ignored.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		switch http.CanonicalHeaderKey(key) {
+		case "Accept", "Content-Type", "User-Agent", "Authorization":
+			return
+		}
+		c.header.Add(key, value)
+	}
+}
+
+// WithTimeout bounds every attempt at a request by d, including reading the
+// response. Retries, if any, each get their own d.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithTransport makes the client send its requests through rt. The
+// http.Client passed to NewClient is left untouched.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		httpClient := *c.client
+		httpClient.Transport = rt
+		c.client = &httpClient
+	}
+}
+
+// A Logger receives a line for every attempt at a request. *log.Logger
+// satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithLogger makes the client log the method, URL, outcome and duration of
+// every attempt at a request to logger.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithRetryPolicy sets the client's RetryPolicy.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithIdempotencyCache sets the client's IdempotencyCache, which is nil by
+// default.
+func WithIdempotencyCache(cache *IdempotencyCache) ClientOption {
+	return func(c *Client) {
+		c.IdempotencyCache = cache
+	}
+}
@@ -1,11 +1,17 @@
 package balanced
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 )
 
+// ErrInsufficientEscrow is returned by OrderService.CreditBankAccount when the
+// order holds less money in escrow than the amount to credit.
+var ErrInsufficientEscrow = errors.New("balanced: insufficient escrow in order")
+
 type OrderService struct {
 	client *Client
 }
@@ -34,19 +40,37 @@ type OrderLinks struct {
 	Merchant string `json:"merchant"`
 }
 
+// OrderUpdate holds the fields of an order to update with
+// OrderService.UpdateWith. Unset fields are left as they are.
+type OrderUpdate struct {
+	Description     *string                `json:"description,omitempty"`
+	DeliveryAddress *Address               `json:"delivery_address,omitempty"`
+	Meta            map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Validate checks the meta keys of the update.
+func (u *OrderUpdate) Validate() error {
+	if u == nil {
+		return errNoUpdate
+	}
+	problems := updateProblems{}
+	checkMeta(problems, u.Meta)
+	return problems.err()
+}
+
 type orderResponse struct {
-	Orders []Order                `json:"orders"`
-	Meta   map[string]interface{} `json:"meta"`
-	Links  *orderResponseLinks    `json:"links"`
+	Orders []Order             `json:"orders"`
+	Meta   *PaginationParams   `json:"meta"`
+	Links  *orderResponseLinks `json:"links"`
 }
 
 type orderResponseLinks struct {
-	Buyers    string `json:"orders.buyers"`
-	Credits   string `json:"orders.credits"`
-	Debits    string `json:"orders.debits"`
-	Merchant  string `json:"orders.merchant"`
-	Refunds   string `json:"orders.refunds"`
-	Reversals string `json:"orders.reversals"`
+	Buyers    string `json:"orders.buyers" template:"/orders/{orders.self}/buyers"`
+	Credits   string `json:"orders.credits" template:"/orders/{orders.self}/credits"`
+	Debits    string `json:"orders.debits" template:"/orders/{orders.self}/debits"`
+	Merchant  string `json:"orders.merchant" template:"/customers/{orders.merchant}"`
+	Refunds   string `json:"orders.refunds" template:"/orders/{orders.self}/refunds"`
+	Reversals string `json:"orders.reversals" template:"/orders/{orders.self}/reversals"`
 }
 
 type OrderPage struct {
@@ -54,10 +78,16 @@ type OrderPage struct {
 	*PaginationParams
 }
 
+type OrderIterator = Iterator[Order]
+
 func (s *OrderService) Create(customerId string, order *Order) (*Order, *http.Response, error) {
+	return s.CreateContext(context.Background(), customerId, order)
+}
+
+func (s *OrderService) CreateContext(ctx context.Context, customerId string, order *Order) (*Order, *http.Response, error) {
 	path := fmt.Sprintf("/customers/%v/orders", customerId)
 	orderResponse := new(orderResponse)
-	httpResponse, err := s.client.POST(path, nil, order, orderResponse)
+	httpResponse, err := s.client.POSTContext(ctx, path, nil, order, orderResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -65,9 +95,13 @@ func (s *OrderService) Create(customerId string, order *Order) (*Order, *http.Re
 }
 
 func (s *OrderService) Fetch(orderId string) (*Order, *http.Response, error) {
+	return s.FetchContext(context.Background(), orderId)
+}
+
+func (s *OrderService) FetchContext(ctx context.Context, orderId string) (*Order, *http.Response, error) {
 	path := fmt.Sprintf("/orders/%v", orderId)
 	orderResponse := new(orderResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, orderResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, orderResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -75,25 +109,356 @@ func (s *OrderService) Fetch(orderId string) (*Order, *http.Response, error) {
 }
 
 func (s *OrderService) List(args ...interface{}) (*OrderPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *OrderService) ListContext(ctx context.Context, args ...interface{}) (*OrderPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/orders", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *OrderService) ListWith(opts *ListOptions) (*OrderPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *OrderService) ListWithContext(ctx context.Context, opts *ListOptions) (*OrderPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *OrderService) list(ctx context.Context, path string, query map[string]interface{}) (*OrderPage, *http.Response, error) {
 	orderResponse := new(orderResponse)
-	httpResponse, err := s.client.GET("/orders", query, nil, orderResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, orderResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &OrderPage{
 		Orders:           orderResponse.Orders,
-		PaginationParams: NewPaginationParams(orderResponse.Meta),
+		PaginationParams: orderResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of orders at href, such as the Next or Previous
+// link of another page.
+func (s *OrderService) FetchPage(href string) (*OrderPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *OrderService) FetchPageContext(ctx context.Context, href string) (*OrderPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all orders, fetching pages as needed. args are
+// the same as for List.
+func (s *OrderService) Iter(args ...interface{}) *OrderIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *OrderService) IterContext(ctx context.Context, args ...interface{}) *OrderIterator {
+	return newIterator(ctx, "/orders", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *OrderService) IterWith(opts *ListOptions) *OrderIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *OrderService) IterWithContext(ctx context.Context, opts *ListOptions) *OrderIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *OrderService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Order, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Orders, page.PaginationParams, nil
+}
+
 func (s *OrderService) Update(orderId string, params map[string]interface{}) (*Order, *http.Response, error) {
+	return s.UpdateContext(context.Background(), orderId, params)
+}
+
+func (s *OrderService) UpdateContext(ctx context.Context, orderId string, params map[string]interface{}) (*Order, *http.Response, error) {
+	path := fmt.Sprintf("/orders/%v", orderId)
+	orderResponse := new(orderResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, orderResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &orderResponse.Orders[0], httpResponse, nil
+}
+
+// UpdateWith updates the fields of an order set in update, once
+// update.Validate accepts them.
+func (s *OrderService) UpdateWith(orderId string, update *OrderUpdate) (*Order, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), orderId, update)
+}
+
+func (s *OrderService) UpdateWithContext(ctx context.Context, orderId string, update *OrderUpdate) (*Order, *http.Response, error) {
 	path := fmt.Sprintf("/orders/%v", orderId)
 	orderResponse := new(orderResponse)
-	httpResponse, err := s.client.PUT(path, nil, params, orderResponse)
+	httpResponse, err := s.client.putUpdate(ctx, path, update, orderResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &orderResponse.Orders[0], httpResponse, nil
 }
+
+// DebitCard charges the buyer's card represented by cardId, putting the money
+// into the order's escrow.
+func (s *OrderService) DebitCard(orderId, cardId string, debit *Debit) (*Debit, *http.Response, error) {
+	return s.DebitCardContext(context.Background(), orderId, cardId, debit)
+}
+
+func (s *OrderService) DebitCardContext(ctx context.Context, orderId, cardId string, debit *Debit) (*Debit, *http.Response, error) {
+	debit.Order = fmt.Sprintf("/orders/%v", orderId)
+	return s.client.Card.ChargeContext(ctx, cardId, debit)
+}
+
+// DebitBankAccount debits the buyer's bank account represented by accountId,
+// putting the money into the order's escrow.
+func (s *OrderService) DebitBankAccount(orderId, accountId string, debit *Debit) (*Debit, *http.Response, error) {
+	return s.DebitBankAccountContext(context.Background(), orderId, accountId, debit)
+}
+
+func (s *OrderService) DebitBankAccountContext(ctx context.Context, orderId, accountId string, debit *Debit) (*Debit, *http.Response, error) {
+	debit.Order = fmt.Sprintf("/orders/%v", orderId)
+	return s.client.BankAccount.DebitContext(ctx, accountId, debit)
+}
+
+// CreditBankAccount pays the merchant's bank account represented by accountId
+// out of the order's escrow. The order is fetched first, and if it holds less
+// than credit.Amount in escrow, the credit is not attempted and the error
+// matches ErrInsufficientEscrow.
+func (s *OrderService) CreditBankAccount(orderId, accountId string, credit *Credit) (*Credit, *http.Response, error) {
+	return s.CreditBankAccountContext(context.Background(), orderId, accountId, credit)
+}
+
+func (s *OrderService) CreditBankAccountContext(ctx context.Context, orderId, accountId string, credit *Credit) (*Credit, *http.Response, error) {
+	order, httpResponse, err := s.FetchContext(ctx, orderId)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	if order.AmountEscrowed < credit.Amount {
+		return nil, httpResponse, fmt.Errorf("%w: order %v has %d escrowed, cannot credit %d",
+			ErrInsufficientEscrow, orderId, order.AmountEscrowed, credit.Amount)
+	}
+	return s.client.Credit.CreateForOrderContext(ctx, accountId, orderId, credit)
+}
+
+// ListDebits lists the debits into an order. args are the same as for List.
+func (s *OrderService) ListDebits(orderId string, args ...interface{}) (*DebitPage, *http.Response, error) {
+	return s.ListDebitsContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) ListDebitsContext(ctx context.Context, orderId string, args ...interface{}) (*DebitPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/orders/%v/debits", orderId)
+	return s.client.Debit.list(ctx, path, query)
+}
+
+// ListDebitsWith is like ListDebits, but takes typed options, which may be nil.
+func (s *OrderService) ListDebitsWith(orderId string, opts *ListOptions) (*DebitPage, *http.Response, error) {
+	return s.ListDebitsContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) ListDebitsWithContext(ctx context.Context, orderId string, opts *ListOptions) (*DebitPage, *http.Response, error) {
+	return s.ListDebitsContext(ctx, orderId, opts)
+}
+
+// IterDebits returns an iterator over all the debits into an order.
+func (s *OrderService) IterDebits(orderId string, args ...interface{}) *DebitIterator {
+	return s.IterDebitsContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) IterDebitsContext(ctx context.Context, orderId string, args ...interface{}) *DebitIterator {
+	path := fmt.Sprintf("/orders/%v/debits", orderId)
+	return newIterator(ctx, path, args, s.client.Debit.fetchPage)
+}
+
+// IterDebitsWith is like IterDebits, but takes typed options, which may be nil.
+func (s *OrderService) IterDebitsWith(orderId string, opts *ListOptions) *DebitIterator {
+	return s.IterDebitsContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) IterDebitsWithContext(ctx context.Context, orderId string, opts *ListOptions) *DebitIterator {
+	return s.IterDebitsContext(ctx, orderId, opts)
+}
+
+// ListCredits lists the credits out of an order. args are the same as for List.
+func (s *OrderService) ListCredits(orderId string, args ...interface{}) (*CreditPage, *http.Response, error) {
+	return s.ListCreditsContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) ListCreditsContext(ctx context.Context, orderId string, args ...interface{}) (*CreditPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/orders/%v/credits", orderId)
+	return s.client.Credit.list(ctx, path, query)
+}
+
+// ListCreditsWith is like ListCredits, but takes typed options, which may be nil.
+func (s *OrderService) ListCreditsWith(orderId string, opts *ListOptions) (*CreditPage, *http.Response, error) {
+	return s.ListCreditsContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) ListCreditsWithContext(ctx context.Context, orderId string, opts *ListOptions) (*CreditPage, *http.Response, error) {
+	return s.ListCreditsContext(ctx, orderId, opts)
+}
+
+// IterCredits returns an iterator over all the credits out of an order.
+func (s *OrderService) IterCredits(orderId string, args ...interface{}) *CreditIterator {
+	return s.IterCreditsContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) IterCreditsContext(ctx context.Context, orderId string, args ...interface{}) *CreditIterator {
+	path := fmt.Sprintf("/orders/%v/credits", orderId)
+	return newIterator(ctx, path, args, s.client.Credit.fetchPage)
+}
+
+// IterCreditsWith is like IterCredits, but takes typed options, which may be nil.
+func (s *OrderService) IterCreditsWith(orderId string, opts *ListOptions) *CreditIterator {
+	return s.IterCreditsContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) IterCreditsWithContext(ctx context.Context, orderId string, opts *ListOptions) *CreditIterator {
+	return s.IterCreditsContext(ctx, orderId, opts)
+}
+
+// ListRefunds lists the refunds of debits into an order. args are the same as for List.
+func (s *OrderService) ListRefunds(orderId string, args ...interface{}) (*RefundPage, *http.Response, error) {
+	return s.ListRefundsContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) ListRefundsContext(ctx context.Context, orderId string, args ...interface{}) (*RefundPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/orders/%v/refunds", orderId)
+	return s.client.Refund.list(ctx, path, query)
+}
+
+// ListRefundsWith is like ListRefunds, but takes typed options, which may be nil.
+func (s *OrderService) ListRefundsWith(orderId string, opts *ListOptions) (*RefundPage, *http.Response, error) {
+	return s.ListRefundsContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) ListRefundsWithContext(ctx context.Context, orderId string, opts *ListOptions) (*RefundPage, *http.Response, error) {
+	return s.ListRefundsContext(ctx, orderId, opts)
+}
+
+// IterRefunds returns an iterator over all the refunds of debits into an order.
+func (s *OrderService) IterRefunds(orderId string, args ...interface{}) *RefundIterator {
+	return s.IterRefundsContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) IterRefundsContext(ctx context.Context, orderId string, args ...interface{}) *RefundIterator {
+	path := fmt.Sprintf("/orders/%v/refunds", orderId)
+	return newIterator(ctx, path, args, s.client.Refund.fetchPage)
+}
+
+// IterRefundsWith is like IterRefunds, but takes typed options, which may be nil.
+func (s *OrderService) IterRefundsWith(orderId string, opts *ListOptions) *RefundIterator {
+	return s.IterRefundsContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) IterRefundsWithContext(ctx context.Context, orderId string, opts *ListOptions) *RefundIterator {
+	return s.IterRefundsContext(ctx, orderId, opts)
+}
+
+// ListReversals lists the reversals of credits out of an order. args are the same as for List.
+func (s *OrderService) ListReversals(orderId string, args ...interface{}) (*ReversalPage, *http.Response, error) {
+	return s.ListReversalsContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) ListReversalsContext(ctx context.Context, orderId string, args ...interface{}) (*ReversalPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/orders/%v/reversals", orderId)
+	return s.client.Reversal.list(ctx, path, query)
+}
+
+// ListReversalsWith is like ListReversals, but takes typed options, which may be nil.
+func (s *OrderService) ListReversalsWith(orderId string, opts *ListOptions) (*ReversalPage, *http.Response, error) {
+	return s.ListReversalsContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) ListReversalsWithContext(ctx context.Context, orderId string, opts *ListOptions) (*ReversalPage, *http.Response, error) {
+	return s.ListReversalsContext(ctx, orderId, opts)
+}
+
+// IterReversals returns an iterator over all the reversals of credits out of an order.
+func (s *OrderService) IterReversals(orderId string, args ...interface{}) *ReversalIterator {
+	return s.IterReversalsContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) IterReversalsContext(ctx context.Context, orderId string, args ...interface{}) *ReversalIterator {
+	path := fmt.Sprintf("/orders/%v/reversals", orderId)
+	return newIterator(ctx, path, args, s.client.Reversal.fetchPage)
+}
+
+// IterReversalsWith is like IterReversals, but takes typed options, which may be nil.
+func (s *OrderService) IterReversalsWith(orderId string, opts *ListOptions) *ReversalIterator {
+	return s.IterReversalsContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) IterReversalsWithContext(ctx context.Context, orderId string, opts *ListOptions) *ReversalIterator {
+	return s.IterReversalsContext(ctx, orderId, opts)
+}
+
+// ListBuyers lists the customers who paid into an order. args are the same as for List.
+func (s *OrderService) ListBuyers(orderId string, args ...interface{}) (*CustomerPage, *http.Response, error) {
+	return s.ListBuyersContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) ListBuyersContext(ctx context.Context, orderId string, args ...interface{}) (*CustomerPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := fmt.Sprintf("/orders/%v/buyers", orderId)
+	return s.client.Customer.list(ctx, path, query)
+}
+
+// ListBuyersWith is like ListBuyers, but takes typed options, which may be nil.
+func (s *OrderService) ListBuyersWith(orderId string, opts *ListOptions) (*CustomerPage, *http.Response, error) {
+	return s.ListBuyersContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) ListBuyersWithContext(ctx context.Context, orderId string, opts *ListOptions) (*CustomerPage, *http.Response, error) {
+	return s.ListBuyersContext(ctx, orderId, opts)
+}
+
+// IterBuyers returns an iterator over all the customers who paid into an order.
+func (s *OrderService) IterBuyers(orderId string, args ...interface{}) *CustomerIterator {
+	return s.IterBuyersContext(context.Background(), orderId, args...)
+}
+
+func (s *OrderService) IterBuyersContext(ctx context.Context, orderId string, args ...interface{}) *CustomerIterator {
+	path := fmt.Sprintf("/orders/%v/buyers", orderId)
+	return newIterator(ctx, path, args, s.client.Customer.fetchPage)
+}
+
+// IterBuyersWith is like IterBuyers, but takes typed options, which may be nil.
+func (s *OrderService) IterBuyersWith(orderId string, opts *ListOptions) *CustomerIterator {
+	return s.IterBuyersContext(context.Background(), orderId, opts)
+}
+
+func (s *OrderService) IterBuyersWithContext(ctx context.Context, orderId string, opts *ListOptions) *CustomerIterator {
+	return s.IterBuyersContext(ctx, orderId, opts)
+}
@@ -1,29 +1,196 @@
 package balanced
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
 
+// PaginationParams describes a page of a list. Next and Previous are the
+// hrefs of the adjacent pages, which can be passed to the FetchPage method of
+// the corresponding service; they are empty when there is no such page.
 type PaginationParams struct {
-	Limit, Offset, Total int
-	First, Href, Last    string
-	Next, Previous       interface{}
+	Limit    int    `json:"limit"`
+	Offset   int    `json:"offset"`
+	Total    int    `json:"total"`
+	First    string `json:"first"`
+	Href     string `json:"href"`
+	Last     string `json:"last"`
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
 }
 
+// ErrNoPage is returned when fetching a page by an empty href, e.g. the Next
+// link of the last page.
+var ErrNoPage = errors.New("balanced: no such page")
+
+// NewPaginationParams builds PaginationParams from the "meta" object of a
+// decoded list response. Missing and null fields are left at their zero
+// values.
 func NewPaginationParams(meta map[string]interface{}) *PaginationParams {
+	number := func(key string) int {
+		n, _ := meta[key].(float64)
+		return int(n)
+	}
+	str := func(key string) string {
+		s, _ := meta[key].(string)
+		return s
+	}
 	return &PaginationParams{
-		Limit:  int(meta["limit"].(float64)),
-		Offset: int(meta["offset"].(float64)),
-		Total:  int(meta["total"].(float64)),
+		Limit:  number("limit"),
+		Offset: number("offset"),
+		Total:  number("total"),
+
+		First: str("first"),
+		Href:  str("href"),
+		Last:  str("last"),
+
+		Next:     str("next"),
+		Previous: str("previous"),
+	}
+}
+
+// HasNext reports whether there is a page after this one.
+func (p *PaginationParams) HasNext() bool {
+	return p.Next != ""
+}
 
-		First: meta["first"].(string),
-		Href:  meta["href"].(string),
-		Last:  meta["last"].(string),
+// HasPrevious reports whether there is a page before this one.
+func (p *PaginationParams) HasPrevious() bool {
+	return p.Previous != ""
+}
 
-		Next:     meta["next"],
-		Previous: meta["previous"],
+// orEmpty returns p, or empty PaginationParams when a response had no meta
+// object, so that the fields of a page can always be read.
+func (p *PaginationParams) orEmpty() *PaginationParams {
+	if p == nil {
+		return new(PaginationParams)
 	}
+	return p
+}
+
+// ListOptions filters, sorts and paginates the results of a List method. The
+// zero value lists the first page with the API's default limit.
+type ListOptions struct {
+	Limit  int
+	Offset int
+
+	// Sort orders the results by a field, optionally followed by ",asc" or
+	// ",desc", e.g. "created_at,desc".
+	Sort string
+
+	// CreatedAfter and CreatedBefore restrict the results to those created
+	// within a time range.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+
+	// Status restricts the results to those with any of the given statuses,
+	// e.g. Succeeded or Failed.
+	Status []string
+
+	// Meta restricts the results to those with the given meta values.
+	Meta map[string]string
+
+	// Filters restricts the results to those whose fields equal the given
+	// values, e.g. "description" => "Order #123". A key may also carry an
+	// operator, e.g. "amount[>]" => "500". The parameters set by the other
+	// options, such as limit, created_at and meta, cannot be filtered on.
+	Filters map[string]string
+}
+
+// reservedListParams maps the query parameters set by the dedicated fields of
+// ListOptions to the names of those fields.
+var reservedListParams = map[string]string{
+	"limit":      "Limit",
+	"offset":     "Offset",
+	"sort":       "Sort",
+	"status":     "Status",
+	"created_at": "CreatedAfter and CreatedBefore",
+	"meta":       "Meta",
 }
 
-func paginatedArgsToQuery(args []interface{}) map[string]interface{} {
+var sortPattern = regexp.MustCompile(`^[a-z_.]+(,(asc|desc))?$`)
+
+// Validate reports the first problem with o, if any.
+func (o *ListOptions) Validate() error {
+	if o.Limit < 0 {
+		return fmt.Errorf("balanced: invalid list options: negative limit %d", o.Limit)
+	}
+	if o.Offset < 0 {
+		return fmt.Errorf("balanced: invalid list options: negative offset %d", o.Offset)
+	}
+	if o.Sort != "" && !sortPattern.MatchString(o.Sort) {
+		return fmt.Errorf("balanced: invalid list options: sort %q is not of the form \"field[,asc|desc]\"", o.Sort)
+	}
+	if o.CreatedAfter != nil && o.CreatedBefore != nil && !o.CreatedAfter.Before(*o.CreatedBefore) {
+		return fmt.Errorf("balanced: invalid list options: CreatedAfter %v is not before CreatedBefore %v", o.CreatedAfter, o.CreatedBefore)
+	}
+	for _, status := range o.Status {
+		if status == "" {
+			return fmt.Errorf("balanced: invalid list options: empty status")
+		}
+	}
+	for key := range o.Meta {
+		if key == "" {
+			return fmt.Errorf("balanced: invalid list options: empty meta key")
+		}
+	}
+	for key := range o.Filters {
+		if key == "" {
+			return fmt.Errorf("balanced: invalid list options: empty filter field")
+		}
+		if option, ok := reservedListParams[strings.SplitN(key, "[", 2)[0]]; ok {
+			return fmt.Errorf("balanced: invalid list options: filter on %q conflicts with %v", key, option)
+		}
+	}
+	return nil
+}
+
+// addToQuery validates o and adds the query parameters it stands for to
+// params.
+func (o *ListOptions) addToQuery(params map[string]interface{}) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	if o.Limit > 0 {
+		params["limit"] = o.Limit
+	}
+	if o.Offset > 0 {
+		params["offset"] = o.Offset
+	}
+	if o.Sort != "" {
+		params["sort"] = o.Sort
+	}
+	if o.CreatedAfter != nil {
+		params["created_at[>]"] = o.CreatedAfter.UTC().Format(time.RFC3339)
+	}
+	if o.CreatedBefore != nil {
+		params["created_at[<]"] = o.CreatedBefore.UTC().Format(time.RFC3339)
+	}
+	switch len(o.Status) {
+	case 0:
+	case 1:
+		params["status"] = o.Status[0]
+	default:
+		params["status[in]"] = strings.Join(o.Status, ",")
+	}
+	for key, value := range o.Meta {
+		params[fmt.Sprintf("meta[%v]", key)] = value
+	}
+	for key, value := range o.Filters {
+		params[key] = value
+	}
+	return nil
+}
+
+// paginatedArgsToQuery turns the args of a variadic List method, which is
+// kept for compatibility next to its typed ListWith variant, into query
+// parameters. The first int arg is the offset and the second the limit; a
+// map[string]interface{} is copied as is; ListOptions are validated and
+// expanded.
+func paginatedArgsToQuery(args []interface{}) (map[string]interface{}, error) {
 	params := make(map[string]interface{})
 	numInts := 0
 	for _, arg := range args {
@@ -34,14 +201,27 @@ func paginatedArgsToQuery(args []interface{}) map[string]interface{} {
 				params["offset"] = arg
 			} else if numInts == 2 {
 				params["limit"] = arg
+			} else {
+				return nil, fmt.Errorf("balanced: unexpected int list argument %d; only an offset and a limit may be given", arg)
 			}
 		case map[string]interface{}:
 			for k, v := range arg {
 				params[k] = v
 			}
+		case ListOptions:
+			if err := arg.addToQuery(params); err != nil {
+				return nil, err
+			}
+		case *ListOptions:
+			if arg == nil {
+				continue
+			}
+			if err := arg.addToQuery(params); err != nil {
+				return nil, err
+			}
 		default:
-			fmt.Printf("unexpected type %T", arg)
+			return nil, fmt.Errorf("balanced: unexpected list argument of type %T", arg)
 		}
 	}
-	return params
+	return params, nil
 }
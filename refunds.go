@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -33,16 +34,16 @@ type RefundLinks struct {
 }
 
 type refundResponse struct {
-	Refunds []Refund               `json:"refunds"`
-	Links   *refundResponseLinks   `json:"links"`
-	Meta    map[string]interface{} `json:"meta"`
+	Refunds []Refund             `json:"refunds"`
+	Links   *refundResponseLinks `json:"links"`
+	Meta    *PaginationParams    `json:"meta"`
 }
 
 type refundResponseLinks struct {
-	Debit   string `json:"refunds.debit"`
-	Dispute string `json:"refunds.dispute"`
-	Events  string `json:"refunds.events"`
-	Order   string `json:"refunds.order"`
+	Debit   string `json:"refunds.debit" template:"/debits/{refunds.debit}"`
+	Dispute string `json:"refunds.dispute" template:"/disputes/{refunds.dispute}"`
+	Events  string `json:"refunds.events" template:"/refunds/{refunds.self}/events"`
+	Order   string `json:"refunds.order" template:"/orders/{refunds.order}"`
 }
 
 type RefundPage struct {
@@ -50,10 +51,16 @@ type RefundPage struct {
 	*PaginationParams
 }
 
+type RefundIterator = Iterator[Refund]
+
 func (s *RefundService) Fetch(refundId string) (*Refund, *http.Response, error) {
+	return s.FetchContext(context.Background(), refundId)
+}
+
+func (s *RefundService) FetchContext(ctx context.Context, refundId string) (*Refund, *http.Response, error) {
 	path := fmt.Sprintf("/refunds/%v", refundId)
 	refundResponse := new(refundResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, refundResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, refundResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -61,23 +68,102 @@ func (s *RefundService) Fetch(refundId string) (*Refund, *http.Response, error)
 }
 
 func (s *RefundService) List(args ...interface{}) (*RefundPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *RefundService) ListContext(ctx context.Context, args ...interface{}) (*RefundPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/refunds", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *RefundService) ListWith(opts *ListOptions) (*RefundPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *RefundService) ListWithContext(ctx context.Context, opts *ListOptions) (*RefundPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *RefundService) list(ctx context.Context, path string, query map[string]interface{}) (*RefundPage, *http.Response, error) {
 	refundResponse := new(refundResponse)
-	httpResponse, err := s.client.GET("/refunds", query, nil, refundResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, refundResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &RefundPage{
 		Refunds:          refundResponse.Refunds,
-		PaginationParams: NewPaginationParams(refundResponse.Meta),
+		PaginationParams: refundResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of refunds at href, such as the Next or Previous
+// link of another page.
+func (s *RefundService) FetchPage(href string) (*RefundPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *RefundService) FetchPageContext(ctx context.Context, href string) (*RefundPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all refunds, fetching pages as needed. args are
+// the same as for List.
+func (s *RefundService) Iter(args ...interface{}) *RefundIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *RefundService) IterContext(ctx context.Context, args ...interface{}) *RefundIterator {
+	return newIterator(ctx, "/refunds", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *RefundService) IterWith(opts *ListOptions) *RefundIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *RefundService) IterWithContext(ctx context.Context, opts *ListOptions) *RefundIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *RefundService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Refund, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Refunds, page.PaginationParams, nil
+}
+
 func (s *RefundService) Update(refundId string, params map[string]interface{}) (*Refund, *http.Response, error) {
+	return s.UpdateContext(context.Background(), refundId, params)
+}
+
+func (s *RefundService) UpdateContext(ctx context.Context, refundId string, params map[string]interface{}) (*Refund, *http.Response, error) {
+	path := fmt.Sprintf("/refunds/%v", refundId)
+	refundResponse := new(refundResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, refundResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &refundResponse.Refunds[0], httpResponse, nil
+}
+
+// UpdateWith updates the fields of a refund set in update, once
+// update.Validate accepts them.
+func (s *RefundService) UpdateWith(refundId string, update *RefundUpdate) (*Refund, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), refundId, update)
+}
+
+func (s *RefundService) UpdateWithContext(ctx context.Context, refundId string, update *RefundUpdate) (*Refund, *http.Response, error) {
 	path := fmt.Sprintf("/refunds/%v", refundId)
 	refundResponse := new(refundResponse)
-	httpResponse, err := s.client.PUT(path, nil, params, refundResponse)
+	httpResponse, err := s.client.putUpdate(ctx, path, update, refundResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
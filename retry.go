@@ -0,0 +1,161 @@
+package balanced
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// IdempotencyKeyHeader is the request header that carries an idempotency key.
+// POST requests bearing this header are considered safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy configures how Client.Do retries failed requests. Only safe
+// requests (GET, HEAD and OPTIONS) and requests carrying an idempotency key
+// are ever retried; everything else is attempted exactly once.
+//
+// Retrying a POST assumes that the API honours its Idempotency-Key header and
+// does not carry out a request twice under the same key. Should the API
+// ignore the key, a retried charge may be made twice.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. Each following
+	// retry waits Multiplier times longer than the previous one, up to
+	// MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// Jitter is the fraction (0 to 1) of each delay that is randomized, so
+	// that many clients failing at once do not retry in lockstep. A delay d
+	// becomes a random duration in [d*(1-Jitter), d].
+	Jitter float64
+
+	// RetryableStatusCodes lists the HTTP status codes worth retrying.
+	RetryableStatusCodes []int
+
+	// RetryableCategoryCodes lists the ErrorResponseError.CategoryCode values
+	// worth retrying, regardless of the status code.
+	RetryableCategoryCodes []string
+
+	// RetryTransportErrors makes POSTs carrying an idempotency key retried
+	// after transport errors too, e.g. when the connection drops before the
+	// response arrives. The API may have carried out the request then, so
+	// only the idempotency key prevents it from being carried out twice.
+	// Safe requests are retried after transport errors regardless.
+	RetryTransportErrors bool
+}
+
+// DefaultRetryPolicy returns a policy that makes up to 3 attempts, backing off
+// exponentially from 250ms, and retries on rate limiting and server errors.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// randFloat64 is the source of jitter; tests may replace it.
+var randFloat64 = rand.Float64
+
+func isSafeRequest(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+func isRetryableRequest(req *http.Request) bool {
+	return isSafeRequest(req) || req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// shouldRetry reports whether another attempt should follow the attempt-th
+// attempt, which ended with res and err.
+func (p *RetryPolicy) shouldRetry(req *http.Request, res *http.Response, err error, attempt int) bool {
+	if p == nil || err == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+	if req.Context().Err() != nil || !isRetryableRequest(req) {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	errorResponse, ok := err.(*ErrorResponse)
+	if !ok {
+		// A transport error, or a 2xx response whose body could not be read.
+		return res == nil && (isSafeRequest(req) || p.RetryTransportErrors)
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if errorResponse.Response.StatusCode == code {
+			return true
+		}
+	}
+	for _, e := range errorResponse.Errors {
+		for _, code := range p.RetryableCategoryCodes {
+			if e.CategoryCode == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait after the attempt-th attempt. A
+// Retry-After header on res takes precedence when it asks for a longer wait.
+func (p *RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay -= delay * jitter * randFloat64()
+	}
+	d := time.Duration(delay)
+	if res != nil {
+		if secs, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+			if after := time.Duration(secs) * time.Second; after > d {
+				d = after
+			}
+			if p.MaxBackoff > 0 && d > p.MaxBackoff {
+				d = p.MaxBackoff
+			}
+		}
+	}
+	return d
+}
+
+// sleepContext waits for d, returning early with the context's error if ctx
+// is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
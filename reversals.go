@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -29,13 +30,13 @@ type ReversalLinks struct {
 type reversalResponse struct {
 	Reversals []Reversal             `json:"reversals"`
 	Links     *reversalResponseLinks `json:"links"`
-	Meta      map[string]interface{} `json:"meta"`
+	Meta      *PaginationParams      `json:"meta"`
 }
 
 type reversalResponseLinks struct {
-	Credit string `json:"reversals.credit"`
-	Events string `json:"reversals.events"`
-	Order  string `json:"reversal.order"`
+	Credit string `json:"reversals.credit" template:"/credits/{reversals.credit}"`
+	Events string `json:"reversals.events" template:"/reversals/{reversals.self}/events"`
+	Order  string `json:"reversals.order" template:"/orders/{reversals.order}"`
 }
 
 type ReversalPage struct {
@@ -43,10 +44,16 @@ type ReversalPage struct {
 	*PaginationParams
 }
 
+type ReversalIterator = Iterator[Reversal]
+
 func (s *ReversalService) Create(creditId string, reversal *Reversal) (*Reversal, *http.Response, error) {
+	return s.CreateContext(context.Background(), creditId, reversal)
+}
+
+func (s *ReversalService) CreateContext(ctx context.Context, creditId string, reversal *Reversal) (*Reversal, *http.Response, error) {
 	path := fmt.Sprintf("/credits/%v/reversals", creditId)
 	reversalResponse := new(reversalResponse)
-	httpResponse, err := s.client.POST(path, nil, reversal, reversalResponse)
+	httpResponse, err := s.client.idempotentPOSTContext(ctx, path, nil, reversal, reversalResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -54,9 +61,13 @@ func (s *ReversalService) Create(creditId string, reversal *Reversal) (*Reversal
 }
 
 func (s *ReversalService) Fetch(reversalId string) (*Reversal, *http.Response, error) {
+	return s.FetchContext(context.Background(), reversalId)
+}
+
+func (s *ReversalService) FetchContext(ctx context.Context, reversalId string) (*Reversal, *http.Response, error) {
 	path := fmt.Sprintf("/reversals/%v", reversalId)
 	reversalResponse := new(reversalResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, reversalResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, reversalResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -64,23 +75,102 @@ func (s *ReversalService) Fetch(reversalId string) (*Reversal, *http.Response, e
 }
 
 func (s *ReversalService) List(args ...interface{}) (*ReversalPage, *http.Response, error) {
-	// Turns args into a map[string]int with "offset" and "limit" keys
-	query := paginatedArgsToQuery(args)
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *ReversalService) ListContext(ctx context.Context, args ...interface{}) (*ReversalPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/reversals", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *ReversalService) ListWith(opts *ListOptions) (*ReversalPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *ReversalService) ListWithContext(ctx context.Context, opts *ListOptions) (*ReversalPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *ReversalService) list(ctx context.Context, path string, query map[string]interface{}) (*ReversalPage, *http.Response, error) {
 	reversalResponse := new(reversalResponse)
-	httpResponse, err := s.client.GET("/reversals", query, nil, reversalResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, reversalResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
 	return &ReversalPage{
 		Reversals:        reversalResponse.Reversals,
-		PaginationParams: NewPaginationParams(reversalResponse.Meta),
+		PaginationParams: reversalResponse.Meta.orEmpty(),
 	}, httpResponse, nil
 }
 
+// FetchPage fetches the page of reversals at href, such as the Next or Previous
+// link of another page.
+func (s *ReversalService) FetchPage(href string) (*ReversalPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *ReversalService) FetchPageContext(ctx context.Context, href string) (*ReversalPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all reversals, fetching pages as needed. args are
+// the same as for List.
+func (s *ReversalService) Iter(args ...interface{}) *ReversalIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *ReversalService) IterContext(ctx context.Context, args ...interface{}) *ReversalIterator {
+	return newIterator(ctx, "/reversals", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *ReversalService) IterWith(opts *ListOptions) *ReversalIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *ReversalService) IterWithContext(ctx context.Context, opts *ListOptions) *ReversalIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *ReversalService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Reversal, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Reversals, page.PaginationParams, nil
+}
+
 func (s *ReversalService) Update(reversalId string, params map[string]interface{}) (*Reversal, *http.Response, error) {
+	return s.UpdateContext(context.Background(), reversalId, params)
+}
+
+func (s *ReversalService) UpdateContext(ctx context.Context, reversalId string, params map[string]interface{}) (*Reversal, *http.Response, error) {
+	path := fmt.Sprintf("/reversals/%v", reversalId)
+	reversalResponse := new(reversalResponse)
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, params, reversalResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &reversalResponse.Reversals[0], httpResponse, nil
+}
+
+// UpdateWith updates the fields of a reversal set in update, once
+// update.Validate accepts them.
+func (s *ReversalService) UpdateWith(reversalId string, update *ReversalUpdate) (*Reversal, *http.Response, error) {
+	return s.UpdateWithContext(context.Background(), reversalId, update)
+}
+
+func (s *ReversalService) UpdateWithContext(ctx context.Context, reversalId string, update *ReversalUpdate) (*Reversal, *http.Response, error) {
 	path := fmt.Sprintf("/reversals/%v", reversalId)
 	reversalResponse := new(reversalResponse)
-	httpResponse, err := s.client.PUT(path, nil, params, reversalResponse)
+	httpResponse, err := s.client.putUpdate(ctx, path, update, reversalResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
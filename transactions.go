@@ -0,0 +1,326 @@
+package balanced
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TransactionService lists the money movements of a marketplace (debits,
+// credits, refunds, reversals and card holds) together.
+type TransactionService struct {
+	client *Client
+}
+
+// TransactionKind is the kind of resource a Transaction holds.
+type TransactionKind string
+
+const (
+	TransactionDebit    TransactionKind = "debit"
+	TransactionCredit   TransactionKind = "credit"
+	TransactionRefund   TransactionKind = "refund"
+	TransactionReversal TransactionKind = "reversal"
+	TransactionCardHold TransactionKind = "card_hold"
+)
+
+// A Transaction is any one of a Debit, Credit, Refund, Reversal or CardHold.
+// Exactly one of its fields is set; check them in turn, switch on Kind, or
+// type switch on Value:
+//
+//	switch v := transaction.Value().(type) {
+//	case *balanced.Debit:
+//		...
+//	case *balanced.Credit:
+//		...
+//	}
+type Transaction struct {
+	Debit    *Debit
+	Credit   *Credit
+	Refund   *Refund
+	Reversal *Reversal
+	CardHold *CardHold
+}
+
+// Kind returns the kind of resource the transaction holds, or "" if it holds
+// none.
+func (t *Transaction) Kind() TransactionKind {
+	switch {
+	case t.Debit != nil:
+		return TransactionDebit
+	case t.Credit != nil:
+		return TransactionCredit
+	case t.Refund != nil:
+		return TransactionRefund
+	case t.Reversal != nil:
+		return TransactionReversal
+	case t.CardHold != nil:
+		return TransactionCardHold
+	}
+	return ""
+}
+
+// Value returns the resource the transaction holds: a *Debit, *Credit,
+// *Refund, *Reversal or *CardHold.
+func (t *Transaction) Value() interface{} {
+	switch t.Kind() {
+	case TransactionDebit:
+		return t.Debit
+	case TransactionCredit:
+		return t.Credit
+	case TransactionRefund:
+		return t.Refund
+	case TransactionReversal:
+		return t.Reversal
+	case TransactionCardHold:
+		return t.CardHold
+	}
+	return nil
+}
+
+func (t *Transaction) Id() string {
+	switch t.Kind() {
+	case TransactionDebit:
+		return t.Debit.Id
+	case TransactionCredit:
+		return t.Credit.Id
+	case TransactionRefund:
+		return t.Refund.Id
+	case TransactionReversal:
+		return t.Reversal.Id
+	case TransactionCardHold:
+		return t.CardHold.Id
+	}
+	return ""
+}
+
+func (t *Transaction) Href() string {
+	switch t.Kind() {
+	case TransactionDebit:
+		return t.Debit.Href
+	case TransactionCredit:
+		return t.Credit.Href
+	case TransactionRefund:
+		return t.Refund.Href
+	case TransactionReversal:
+		return t.Reversal.Href
+	case TransactionCardHold:
+		return t.CardHold.Href
+	}
+	return ""
+}
+
+// Amount returns the amount of the transaction, in cents.
+func (t *Transaction) Amount() int {
+	switch t.Kind() {
+	case TransactionDebit:
+		return t.Debit.Amount
+	case TransactionCredit:
+		return t.Credit.Amount
+	case TransactionRefund:
+		return t.Refund.Amount
+	case TransactionReversal:
+		return t.Reversal.Amount
+	case TransactionCardHold:
+		return t.CardHold.Amount
+	}
+	return 0
+}
+
+func (t *Transaction) CreatedAt() *time.Time {
+	switch t.Kind() {
+	case TransactionDebit:
+		return t.Debit.CreatedAt
+	case TransactionCredit:
+		return t.Credit.CreatedAt
+	case TransactionRefund:
+		return t.Refund.CreatedAt
+	case TransactionReversal:
+		return t.Reversal.CreatedAt
+	case TransactionCardHold:
+		return t.CardHold.CreatedAt
+	}
+	return nil
+}
+
+// transactionResponse is the response to a listing of transactions, which
+// groups them by kind.
+type transactionResponse struct {
+	Debits    []Debit           `json:"debits"`
+	Credits   []Credit          `json:"credits"`
+	Refunds   []Refund          `json:"refunds"`
+	Reversals []Reversal        `json:"reversals"`
+	CardHolds []CardHold        `json:"card_holds"`
+	Meta      *PaginationParams `json:"meta"`
+}
+
+// transactions merges the transactions of the response in the order of
+// the list at path with query: by creation time, newest first unless the list
+// is sorted by ascending creation time. When it is sorted by another field,
+// which the merge cannot follow, the transactions are only grouped by kind.
+func (r *transactionResponse) transactions(path string, query map[string]interface{}) []Transaction {
+	var transactions []Transaction
+	for i := range r.Debits {
+		transactions = append(transactions, Transaction{Debit: &r.Debits[i]})
+	}
+	for i := range r.Credits {
+		transactions = append(transactions, Transaction{Credit: &r.Credits[i]})
+	}
+	for i := range r.Refunds {
+		transactions = append(transactions, Transaction{Refund: &r.Refunds[i]})
+	}
+	for i := range r.Reversals {
+		transactions = append(transactions, Transaction{Reversal: &r.Reversals[i]})
+	}
+	for i := range r.CardHolds {
+		transactions = append(transactions, Transaction{CardHold: &r.CardHolds[i]})
+	}
+	field, ascending := listSort(path, query)
+	if field != "" && field != "created_at" {
+		return transactions
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		a, b := transactions[i].CreatedAt(), transactions[j].CreatedAt()
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if ascending {
+			return a.Before(*b)
+		}
+		return a.After(*b)
+	})
+	return transactions
+}
+
+// listSort returns the field by which the list at path with query is sorted
+// and whether it is sorted in ascending order, as told by the sort parameter
+// of query or else of path, e.g. "created_at,asc". The field is empty when
+// there is no sort parameter.
+func listSort(path string, query map[string]interface{}) (field string, ascending bool) {
+	param, ok := query["sort"]
+	if !ok {
+		if u, err := url.Parse(path); err == nil && u.Query().Get("sort") != "" {
+			param, ok = u.Query().Get("sort"), true
+		}
+	}
+	if !ok {
+		return "", false
+	}
+	parts := strings.SplitN(fmt.Sprint(param), ",", 2)
+	return parts[0], len(parts) == 2 && parts[1] == "asc"
+}
+
+// TransactionPage holds a paginated set of transactions, in the order of the
+// list: newest first unless sorted otherwise.
+type TransactionPage struct {
+	Transactions []Transaction
+	*PaginationParams
+}
+
+type TransactionIterator = Iterator[Transaction]
+
+// Fetch fetches the transaction with the given id, telling its kind from the
+// prefix of the id (e.g., "WD" for debits and "CR" for credits).
+func (s *TransactionService) Fetch(transactionId string) (*Transaction, *http.Response, error) {
+	return s.FetchContext(context.Background(), transactionId)
+}
+
+func (s *TransactionService) FetchContext(ctx context.Context, transactionId string) (*Transaction, *http.Response, error) {
+	transaction := new(Transaction)
+	var httpResponse *http.Response
+	var err error
+	switch {
+	case strings.HasPrefix(transactionId, "WD"):
+		transaction.Debit, httpResponse, err = s.client.Debit.FetchContext(ctx, transactionId)
+	case strings.HasPrefix(transactionId, "CR"):
+		transaction.Credit, httpResponse, err = s.client.Credit.FetchContext(ctx, transactionId)
+	case strings.HasPrefix(transactionId, "RF"):
+		transaction.Refund, httpResponse, err = s.client.Refund.FetchContext(ctx, transactionId)
+	case strings.HasPrefix(transactionId, "RV"):
+		transaction.Reversal, httpResponse, err = s.client.Reversal.FetchContext(ctx, transactionId)
+	case strings.HasPrefix(transactionId, "HL"):
+		transaction.CardHold, httpResponse, err = s.client.CardHold.FetchContext(ctx, transactionId)
+	default:
+		return nil, nil, fmt.Errorf("balanced: unknown kind of transaction %q", transactionId)
+	}
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return transaction, httpResponse, nil
+}
+
+func (s *TransactionService) List(args ...interface{}) (*TransactionPage, *http.Response, error) {
+	return s.ListContext(context.Background(), args...)
+}
+
+func (s *TransactionService) ListContext(ctx context.Context, args ...interface{}) (*TransactionPage, *http.Response, error) {
+	query, err := paginatedArgsToQuery(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.list(ctx, "/transactions", query)
+}
+
+// ListWith is like List, but takes typed options, which may be nil.
+func (s *TransactionService) ListWith(opts *ListOptions) (*TransactionPage, *http.Response, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+func (s *TransactionService) ListWithContext(ctx context.Context, opts *ListOptions) (*TransactionPage, *http.Response, error) {
+	return s.ListContext(ctx, opts)
+}
+
+func (s *TransactionService) list(ctx context.Context, path string, query map[string]interface{}) (*TransactionPage, *http.Response, error) {
+	transactionResponse := new(transactionResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, query, nil, transactionResponse)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	return &TransactionPage{
+		Transactions:     transactionResponse.transactions(path, query),
+		PaginationParams: transactionResponse.Meta.orEmpty(),
+	}, httpResponse, nil
+}
+
+// FetchPage fetches the page of transactions at href, such as the Next or
+// Previous link of another page.
+func (s *TransactionService) FetchPage(href string) (*TransactionPage, *http.Response, error) {
+	return s.FetchPageContext(context.Background(), href)
+}
+
+func (s *TransactionService) FetchPageContext(ctx context.Context, href string) (*TransactionPage, *http.Response, error) {
+	if href == "" {
+		return nil, nil, ErrNoPage
+	}
+	return s.list(ctx, href, nil)
+}
+
+// Iter returns an iterator over all transactions, fetching pages as needed.
+// args are the same as for List.
+func (s *TransactionService) Iter(args ...interface{}) *TransactionIterator {
+	return s.IterContext(context.Background(), args...)
+}
+
+func (s *TransactionService) IterContext(ctx context.Context, args ...interface{}) *TransactionIterator {
+	return newIterator(ctx, "/transactions", args, s.fetchPage)
+}
+
+// IterWith is like Iter, but takes typed options, which may be nil.
+func (s *TransactionService) IterWith(opts *ListOptions) *TransactionIterator {
+	return s.IterContext(context.Background(), opts)
+}
+
+func (s *TransactionService) IterWithContext(ctx context.Context, opts *ListOptions) *TransactionIterator {
+	return s.IterContext(ctx, opts)
+}
+
+func (s *TransactionService) fetchPage(ctx context.Context, path string, query map[string]interface{}) ([]Transaction, *PaginationParams, error) {
+	page, _, err := s.list(ctx, path, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page.Transactions, page.PaginationParams, nil
+}
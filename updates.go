@@ -0,0 +1,112 @@
+package balanced
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// String returns a pointer to v, for setting the fields of update structs:
+//
+//	debit, _, err := client.Debit.UpdateWith(debitId, &balanced.DebitUpdate{
+//		Description: balanced.String("Order #1234"),
+//	})
+func String(v string) *string { return &v }
+
+// Int returns a pointer to v, for setting the fields of update structs.
+func Int(v int) *int { return &v }
+
+// DescriptionUpdate holds the fields of a resource whose description and meta
+// are all that can be updated. Unset fields are left as they are. V is the
+// type of the meta values of the resource.
+type DescriptionUpdate[V any] struct {
+	Description *string      `json:"description,omitempty"`
+	Meta        map[string]V `json:"meta,omitempty"`
+}
+
+// Updates for DebitService.UpdateWith and the like.
+type (
+	DebitUpdate    = DescriptionUpdate[string]
+	CreditUpdate   = DescriptionUpdate[interface{}]
+	RefundUpdate   = DescriptionUpdate[string]
+	ReversalUpdate = DescriptionUpdate[string]
+	CardHoldUpdate = DescriptionUpdate[interface{}]
+)
+
+// Validate checks that the meta keys are not blank.
+func (u *DescriptionUpdate[V]) Validate() error {
+	if u == nil {
+		return errNoUpdate
+	}
+	problems := updateProblems{}
+	checkMeta(problems, u.Meta)
+	return problems.err()
+}
+
+// errNoUpdate is returned by the UpdateWith methods when given a nil update.
+var errNoUpdate = fmt.Errorf("%w: no update", ErrValidation)
+
+// A validator is the update given to an UpdateWith method.
+type validator interface {
+	Validate() error
+}
+
+// putUpdate sends u to path once it is valid, decoding the response into v.
+func (c *Client) putUpdate(ctx context.Context, path string, u validator, v interface{}) (*http.Response, error) {
+	if err := u.Validate(); err != nil {
+		return nil, err
+	}
+	return c.PUTContext(ctx, path, nil, u, v)
+}
+
+// updateProblems collects what is wrong with the fields of an update, by field
+// name.
+type updateProblems map[string]string
+
+// checkMeta records a problem with meta in p if it has a blank key.
+func checkMeta[V any](p updateProblems, meta map[string]V) {
+	for key := range meta {
+		if strings.TrimSpace(key) == "" {
+			p["meta"] = "keys must not be blank"
+		}
+	}
+}
+
+// isDigits reports whether s is made of n decimal digits.
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// err returns a *ValidationError listing the problems, or nil if there are
+// none. Like the validation errors of the API, it matches ErrValidation.
+func (p updateProblems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	fields := make([]string, 0, len(p))
+	for field := range p {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	descrs := make([]string, len(fields))
+	for i, field := range fields {
+		descrs[i] = field + " " + p[field]
+	}
+	return &ValidationError{
+		ErrorResponseError: &ErrorResponseError{
+			CategoryType: "request",
+			Description:  "invalid update: " + strings.Join(descrs, "; "),
+		},
+		Fields: p,
+	}
+}
@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -28,8 +29,8 @@ type Verification struct {
 	Attempts           int                `json:"attempts"`           // e.g., 0
 	AttemptsRemaining  int                `json:"attempts_remaining"` // e.g., 3
 	DepositStatus      string             `json:"deposit_status"`     // e.g., "succeeded"
-	Href               string             `json"href"`                // e.g., "/verifications/BZ25cVCn6wh6UZrfgFcF71RD"
-	Id                 string             `json"id"`                  // e.g., "BZ25cVCn6wh6UZrfgFcF71RD"
+	Href               string             `json:"href"`               // e.g., "/verifications/BZ25cVCn6wh6UZrfgFcF71RD"
+	Id                 string             `json:"id"`                 // e.g., "BZ25cVCn6wh6UZrfgFcF71RD"
 	Links              *VerificationLinks `json:"links"`              // e.g., "bank_account" => "BA1RdDM12aF5N8WVA1kaewQZ"
 	Meta               map[string]string  `json:"meta"`
 	VerificationStatus string             `json:"verification_status"` // e.g., "pending"
@@ -43,18 +44,28 @@ type VerificationLinks struct {
 
 type verificationResponse struct {
 	Verifications []Verification             `json:"bank_account_verifications"`
-	Meta          map[string]interface{}     `json:"meta"`
+	Meta          *PaginationParams          `json:"meta"`
 	Links         *verificationResponseLinks `json:"links"`
 }
 
+// VerificationPage holds a paginated set of verifications
+type VerificationPage struct {
+	Verifications []Verification
+	*PaginationParams
+}
+
 type verificationResponseLinks struct {
-	BankAccount string `json:"bank_account_verifications.bank_account"`
+	BankAccount string `json:"bank_account_verifications.bank_account" template:"/bank_accounts/{bank_account_verifications.bank_account}"`
 }
 
 func (s *VerificationService) Create(accountId string) (*Verification, *http.Response, error) {
+	return s.CreateContext(context.Background(), accountId)
+}
+
+func (s *VerificationService) CreateContext(ctx context.Context, accountId string) (*Verification, *http.Response, error) {
 	path := fmt.Sprintf("/bank_accounts/%v/verifications", accountId)
 	verifResponse := new(verificationResponse)
-	httpResponse, err := s.client.POST(path, nil, nil, verifResponse)
+	httpResponse, err := s.client.POSTContext(ctx, path, nil, nil, verifResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -63,9 +74,13 @@ func (s *VerificationService) Create(accountId string) (*Verification, *http.Res
 
 // Fetches the verification for a bank account
 func (s *VerificationService) Fetch(verificationId string) (*Verification, *http.Response, error) {
+	return s.FetchContext(context.Background(), verificationId)
+}
+
+func (s *VerificationService) FetchContext(ctx context.Context, verificationId string) (*Verification, *http.Response, error) {
 	path := fmt.Sprintf("/verifications/%v", verificationId)
 	verifResponse := new(verificationResponse)
-	httpResponse, err := s.client.GET(path, nil, nil, verifResponse)
+	httpResponse, err := s.client.GETContext(ctx, path, nil, nil, verifResponse)
 	if err != nil {
 		return nil, httpResponse, err
 	}
@@ -80,9 +95,13 @@ type ConfirmationAmounts struct {
 }
 
 func (s *VerificationService) Confirm(verificationId string, amount1 int, amount2 int) (*Verification, *http.Response, error) {
+	return s.ConfirmContext(context.Background(), verificationId, amount1, amount2)
+}
+
+func (s *VerificationService) ConfirmContext(ctx context.Context, verificationId string, amount1 int, amount2 int) (*Verification, *http.Response, error) {
 	path := fmt.Sprintf("/verifications/%v", verificationId)
 	verifResponse := new(verificationResponse)
-	httpResponse, err := s.client.PUT(path, nil, &ConfirmationAmounts{
+	httpResponse, err := s.client.PUTContext(ctx, path, nil, &ConfirmationAmounts{
 		Amount1: amount1,
 		Amount2: amount2,
 	}, verifResponse)
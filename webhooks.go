@@ -0,0 +1,289 @@
+package balanced
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// maxWebhookBodyLen bounds the size of the callback bodies WebhookHandler
+// reads.
+const maxWebhookBodyLen = 1 << 20
+
+// An EventHandlerFunc handles an event received by a WebhookHandler. When it
+// returns an error, the callback is answered with a server error so that
+// Balanced sends it again later.
+type EventHandlerFunc func(ctx context.Context, event *Event) error
+
+// WebhookHandler is an http.Handler receiving the events Balanced sends to the
+// URLs registered with CallbackService.Create, and dispatching them to
+// handlers by event type:
+//
+//	webhooks := balanced.NewWebhookHandler()
+//	webhooks.Handle(balanced.EventDebitSucceeded, func(ctx context.Context, event *balanced.Event) error {
+//		debit, err := event.Debit()
+//		...
+//	})
+//	http.Handle("/balanced/callbacks", webhooks)
+//
+// A callback is answered with 200 OK when its events were handled or have no
+// handler, 500 Internal Server Error when a handler failed, 400 Bad Request
+// when its body is not an event, and 405 Method Not Allowed unless it is a
+// POST, PUT or GET. Callbacks by GET carry no entity, so they are answered
+// with 400 Bad Request unless the handler was made WithEventVerification,
+// which fetches the event with its entity.
+type WebhookHandler struct {
+	handlers map[string]EventHandlerFunc
+	logger   Logger
+
+	// verifier, when set, verifies the received events against Balanced.
+	verifier *eventVerifier
+
+	// store, when set, records the events handled.
+	store EventStore
+}
+
+// A WebhookOption configures a WebhookHandler.
+type WebhookOption func(*WebhookHandler)
+
+// WithWebhookLogger makes the handler log the callbacks it rejects and the
+// errors of event handlers to logger.
+func WithWebhookLogger(logger Logger) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.logger = logger
+	}
+}
+
+// WithEventVerification makes the handler verify every received event before
+// handling it, as callbacks are not authenticated: the event of the same id is
+// fetched from Balanced with client, and the callback is rejected with 403
+// Forbidden if there is no such event or it differs in type or entity. The
+// handlers are then given the fetched event. Fetched events are cached, so
+// that retried callbacks do not fetch them again.
+func WithEventVerification(client *Client) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.verifier = &eventVerifier{
+			events:  client.Event,
+			cache:   make(map[string]*Event),
+			maxSize: maxVerifiedEvents,
+		}
+	}
+}
+
+// NewWebhookHandler returns a handler with no event handlers registered.
+func NewWebhookHandler(opts ...WebhookOption) *WebhookHandler {
+	h := &WebhookHandler{handlers: make(map[string]EventHandlerFunc)}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Handle registers fn to handle the events of type eventType (e.g.,
+// "debit.succeeded"), replacing any handler registered before. Events
+// received by GET reach fn only if the handler was made WithEventVerification,
+// which fetches their entity.
+func (h *WebhookHandler) Handle(eventType string, fn EventHandlerFunc) {
+	h.handlers[eventType] = fn
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var events []Event
+	switch r.Method {
+	case "POST", "PUT":
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyLen))
+		if err == nil {
+			events, err = decodeWebhookEvents(body)
+		}
+		if err != nil {
+			h.reject(w, http.StatusBadRequest, err)
+			return
+		}
+	case "GET":
+		// Callbacks by GET only carry the id and type of the event; the
+		// verifier fetches the rest.
+		if h.verifier == nil {
+			h.reject(w, http.StatusBadRequest, errors.New("GET callbacks require WithEventVerification"))
+			return
+		}
+		query := r.URL.Query()
+		event := Event{Id: query.Get("id"), Type: query.Get("type")}
+		if event.Id == "" || event.Type == "" {
+			h.reject(w, http.StatusBadRequest, errors.New("missing event id or type"))
+			return
+		}
+		events = []Event{event}
+	default:
+		w.Header().Set("Allow", "POST, PUT, GET")
+		h.reject(w, http.StatusMethodNotAllowed, errors.New(r.Method+" not allowed"))
+		return
+	}
+
+	for i := range events {
+		event := &events[i]
+		if h.verifier != nil {
+			verified, err := h.verifier.verify(r.Context(), event)
+			if err != nil {
+				code := http.StatusInternalServerError
+				var verificationErr *EventVerificationError
+				if errors.As(err, &verificationErr) {
+					code = http.StatusForbidden
+				}
+				h.reject(w, code, err)
+				return
+			}
+			event = verified
+		}
+		if err := h.handle(r.Context(), event); err != nil {
+			h.reject(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handle dispatches event to its handler, if there is one and the event was
+// not handled before.
+func (h *WebhookHandler) handle(ctx context.Context, event *Event) error {
+	fn, ok := h.handlers[event.Type]
+	if !ok {
+		return nil
+	}
+	if h.store != nil {
+		claimed, err := h.store.Claim(ctx, event.Id)
+		if err != nil || !claimed {
+			return err
+		}
+	}
+	if err := fn(ctx, event); err != nil {
+		if h.store != nil {
+			if releaseErr := h.store.Release(ctx, event.Id); releaseErr != nil && h.logger != nil {
+				h.logger.Printf("balanced: webhook: cannot release event %v: %v", event.Id, releaseErr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func (h *WebhookHandler) reject(w http.ResponseWriter, code int, err error) {
+	if h.logger != nil {
+		h.logger.Printf("balanced: webhook: %d %v", code, err)
+	}
+	http.Error(w, http.StatusText(code), code)
+}
+
+// decodeWebhookEvents decodes the body of a callback: either an event, or an
+// envelope of events as returned by EventService.List.
+func decodeWebhookEvents(body []byte) ([]Event, error) {
+	var payload struct {
+		Event
+		Events []Event `json:"events"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	events := payload.Events
+	if events == nil {
+		events = []Event{payload.Event}
+	}
+	if len(events) == 0 {
+		return nil, errors.New("no events")
+	}
+	for _, event := range events {
+		if event.Id == "" || event.Type == "" {
+			return nil, errors.New("missing event id or type")
+		}
+	}
+	return events, nil
+}
+
+// EventVerificationError reports a received event that could not be verified
+// against Balanced.
+type EventVerificationError struct {
+	EventId string
+	Reason  string // e.g., "no such event"
+}
+
+func (e *EventVerificationError) Error() string {
+	return fmt.Sprintf("balanced: cannot verify event %v: %v", e.EventId, e.Reason)
+}
+
+// maxVerifiedEvents bounds the number of events an eventVerifier caches.
+const maxVerifiedEvents = 1000
+
+// eventVerifier verifies received events by fetching them from Balanced.
+type eventVerifier struct {
+	events  *EventService
+	maxSize int
+
+	mu    sync.Mutex
+	cache map[string]*Event
+	order []string // ids of the cached events, oldest first
+}
+
+// verify returns the event fetched from Balanced with the id of received, or
+// an *EventVerificationError if received does not match it.
+func (v *eventVerifier) verify(ctx context.Context, received *Event) (*Event, error) {
+	fetched, err := v.fetch(ctx, received.Id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, &EventVerificationError{EventId: received.Id, Reason: "no such event"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if fetched.Type != received.Type {
+		return nil, &EventVerificationError{
+			EventId: received.Id,
+			Reason:  fmt.Sprintf("type is %v, not %v", fetched.Type, received.Type),
+		}
+	}
+	// Callbacks by GET carry no entity to compare.
+	if received.Entity != nil {
+		a, err := json.Marshal(received.Entity)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(fetched.Entity)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(a, b) {
+			return nil, &EventVerificationError{EventId: received.Id, Reason: "entity differs"}
+		}
+	}
+	return fetched, nil
+}
+
+// fetch returns the event with the given id, from the cache if it was
+// fetched before.
+func (v *eventVerifier) fetch(ctx context.Context, eventId string) (*Event, error) {
+	v.mu.Lock()
+	event, ok := v.cache[eventId]
+	v.mu.Unlock()
+	if ok {
+		return event, nil
+	}
+
+	event, _, err := v.events.FetchContext(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.cache[eventId]; !ok {
+		v.order = append(v.order, eventId)
+	}
+	v.cache[eventId] = event
+	for len(v.order) > v.maxSize {
+		delete(v.cache, v.order[0])
+		v.order = v.order[1:]
+	}
+	return event, nil
+}